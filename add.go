@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olbrichattila/creategofra/internal/appwizard"
+	"github.com/olbrichattila/creategofra/internal/dockerwizard"
+)
+
+func runAdd(args []string) int {
+	flags := flag.NewFlagSet("add", flag.ContinueOnError)
+	projectDir := flags.String("dir", ".", "directory of the existing project")
+	answerFlags, err := newAnswerFlags(flags, args)
+	if err != nil {
+		return report(stepFailed("load questions", invalidInput, err))
+	}
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintln(out, "Usage creategofra add <feature> [flags]")
+		fmt.Fprintln(out, "\nAdds a feature to an existing project and regenerates its docker-compose.yml.")
+		fmt.Fprintf(out, "\nFeatures:\n  %s\n", strings.Join(addFeatures(), "\n  "))
+		fmt.Fprintln(out, "\nFlags:")
+		answerFlags.printDefaults(flags)
+	}
+
+	positional, err := parseFlags(flags, args)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(positional) != 1 {
+		fmt.Println("Usage creategofra add <feature> [flags], see creategofra add --help")
+		return exitUsage
+	}
+
+	feature := positional[0]
+	if !contains(feature, addFeatures()) {
+		fmt.Printf("Unknown feature '%s', available: %s\n", feature, strings.Join(addFeatures(), ", "))
+		return exitUsage
+	}
+
+	options, err := answerFlags.options()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+
+	return report(addFeature(*projectDir, feature, options))
+}
+
+func addFeature(projectDir, feature string, options appwizard.Options) error {
+	envFileName := filepath.Join(projectDir, ".env")
+	envContent, err := os.ReadFile(envFileName)
+	if err != nil {
+		return stepFailed("read .env", ioFailure, fmt.Errorf("'%s' is not a project directory: %w", projectDir, err))
+	}
+
+	responses, storages := appwizard.ParseEnv(string(envContent))
+	if feature != "docker" {
+		var envStr string
+		responses, storages, envStr, err = appwizard.AddFeature(string(envContent), feature, options)
+		if err != nil {
+			return stepFailed("add "+feature, invalidInput, err)
+		}
+
+		if err := os.WriteFile(envFileName, []byte(envStr), 0644); err != nil {
+			return stepFailed("write .env", ioFailure, err)
+		}
+	}
+
+	dockerComposeFileContent := dockerwizard.Wizard(getDbConnection(responses), responses, storages, hasMailConfig(responses))
+	err = os.WriteFile(filepath.Join(projectDir, "docker-compose.yml"), []byte(dockerComposeFileContent), 0644)
+
+	return stepFailed("write docker-compose.yml", ioFailure, err)
+}
+
+// addFeatures returns the features of the wizard and docker, which only regenerates docker-compose.yml
+func addFeatures() []string {
+	return append([]string{"docker"}, appwizard.Features()...)
+}
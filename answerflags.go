@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/olbrichattila/creategofra/internal/appwizard"
+)
+
+// answerFlags registers the answers file, the questions file and a flag for every wizard question on a flag set
+type answerFlags struct {
+	answersFile *string
+	escConfirms *bool
+	answers     appwizard.Answers
+	wizardFlags []appwizard.Flag
+}
+
+// newAnswerFlags loads the questions file given in the arguments first, as the flags are generated from the questions
+func newAnswerFlags(flags *flag.FlagSet, args []string) (*answerFlags, error) {
+	if questionsFile := flagValue(args, "questions"); questionsFile != "" {
+		if err := appwizard.LoadQuestions(questionsFile); err != nil {
+			return nil, err
+		}
+	}
+
+	flags.String("questions", "", "questions file (.yaml or .json) extending or overriding the questions of the wizard")
+	f := &answerFlags{
+		answersFile: flags.String("answers", "", "answers file (.yaml or .json), questions are not asked interactively"),
+		escConfirms: flags.Bool("esc-confirms", false, "Esc confirms an answer instead of aborting like Ctrl-C"),
+		answers:     make(appwizard.Answers),
+		wizardFlags: appwizard.Flags(),
+	}
+
+	for _, wizardFlag := range f.wizardFlags {
+		// Var panics on a flag defined twice, a question of a template may take the name of a built-in flag
+		if flags.Lookup(wizardFlag.Name) != nil || wizardFlag.Name == "help" || wizardFlag.Name == "h" {
+			return nil, fmt.Errorf("question %s: flag --%s is already used, set another flag name for the question", wizardFlag.Key, wizardFlag.Name)
+		}
+		flags.Var(&answerFlag{answers: f.answers, key: wizardFlag.Key}, wizardFlag.Name, wizardFlag.Usage)
+	}
+
+	return f, nil
+}
+
+// options merges the answers file with the flags, the flags take precedence
+func (f *answerFlags) options() (appwizard.Options, error) {
+	options := appwizard.Options{Answers: make(appwizard.Answers), EscConfirms: *f.escConfirms}
+	if *f.answersFile != "" {
+		answers, err := appwizard.LoadAnswers(*f.answersFile)
+		if err != nil {
+			return options, err
+		}
+		options.Answers = answers
+		options.NonInteractive = true
+	}
+
+	for key, value := range f.answers {
+		options.Answers[key] = value
+	}
+
+	return options, nil
+}
+
+// printDefaults prints the flags of the command, then the wizard flags with their default and allowed values
+func (f *answerFlags) printDefaults(flags *flag.FlagSet) {
+	out := flags.Output()
+	wizardFlags := make(map[string]bool, len(f.wizardFlags))
+	for _, wizardFlag := range f.wizardFlags {
+		wizardFlags[wizardFlag.Name] = true
+	}
+
+	flags.VisitAll(func(commandFlag *flag.Flag) {
+		if wizardFlags[commandFlag.Name] {
+			return
+		}
+
+		typeName, usage := flag.UnquoteUsage(commandFlag)
+		fmt.Fprintf(out, "  --%s\n", strings.TrimSpace(commandFlag.Name+" "+typeName))
+		if commandFlag.DefValue != "" && commandFlag.DefValue != "false" {
+			usage += fmt.Sprintf(" (default %q)", commandFlag.DefValue)
+		}
+		fmt.Fprintf(out, "\t%s\n", usage)
+	})
+
+	for _, wizardFlag := range f.wizardFlags {
+		fmt.Fprintf(out, "  --%s %s\n", wizardFlag.Name, wizardFlag.Type)
+		fmt.Fprintf(out, "\t%s (%s)\n", wizardFlag.Usage, wizardFlag.Key)
+		if len(wizardFlag.Defaults) > 0 {
+			fmt.Fprintf(out, "\tdefault: %s\n", strings.Join(wizardFlag.Defaults, ", "))
+		}
+		if len(wizardFlag.Values) > 0 {
+			fmt.Fprintf(out, "\tallowed: %s\n", strings.Join(wizardFlag.Values, ", "))
+		}
+	}
+}
+
+// answerFlag stores the value of a command-line flag as the answer of a question
+type answerFlag struct {
+	answers appwizard.Answers
+	key     string
+}
+
+func (f *answerFlag) String() string {
+	if f.answers == nil {
+		return ""
+	}
+
+	return f.answers[f.key]
+}
+
+func (f *answerFlag) Set(value string) error {
+	f.answers[f.key] = value
+	return nil
+}
+
+// flagValue returns the value of a flag from the arguments before they are parsed
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		for _, prefix := range []string{"-", "--"} {
+			if arg == prefix+name && i+1 < len(args) {
+				return args[i+1]
+			}
+
+			if value, ok := strings.CutPrefix(arg, prefix+name+"="); ok {
+				return value
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseFlags parses flags placed before, between or after the positional arguments
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	positional := make([]string, 0)
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
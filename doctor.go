@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/olbrichattila/creategofra/internal/specio"
+)
+
+type doctorCheck struct {
+	name     string
+	command  []string
+	required bool
+	purpose  string
+}
+
+var doctorChecks = []doctorCheck{
+	{name: "go", command: []string{"go", "version"}, required: true, purpose: "initializes the module of the generated project"},
+	{name: "docker", command: []string{"docker", "--version"}, purpose: "runs the services of the generated docker-compose.yml"},
+	{name: "docker compose", command: []string{"docker", "compose", "version"}, purpose: "starts the generated docker-compose.yml"},
+}
+
+func runDoctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintln(out, "Usage creategofra doctor")
+		fmt.Fprintln(out, "\nChecks the tools used by creategofra and the generated projects.")
+		fmt.Fprintln(out, "Exits with 1 if a required tool is missing.")
+	}
+
+	positional, err := parseFlags(flags, args)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+
+	if err != nil || len(positional) != 0 {
+		return exitUsage
+	}
+
+	result := exitOK
+	for _, check := range doctorChecks {
+		output, err := exec.Command(check.command[0], check.command[1:]...).CombinedOutput()
+		if err == nil {
+			fmt.Printf("[ok]      %s: %s\n", check.name, firstLine(string(output)))
+			continue
+		}
+
+		if check.required {
+			result = exitFailure
+			fmt.Printf("[missing] %s: required, %s\n", check.name, check.purpose)
+			continue
+		}
+
+		fmt.Printf("[warning] %s: optional, %s\n", check.name, check.purpose)
+	}
+
+	if !specio.IsTerminal() {
+		fmt.Println("[warning] terminal: stdin is not a terminal, the answers are read line by line, or use --answers")
+	}
+
+	return result
+}
+
+func firstLine(s string) string {
+	return strings.TrimSpace(strings.Split(s, "\n")[0])
+}
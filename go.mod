@@ -2,6 +2,12 @@ module github.com/olbrichattila/creategofra
 
 go 1.23.1
 
-require github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
+require (
+	github.com/eiannone/keyboard v0.0.0-20220611211555-0d226195f203
+	github.com/rivo/uniseg v0.4.7
+	golang.org/x/mod v0.22.0
+	golang.org/x/term v0.0.0-20220526004731-065cf7ba2467
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require golang.org/x/sys v0.0.0-20220520151302-bc2c85ada10a // indirect
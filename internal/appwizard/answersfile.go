@@ -0,0 +1,89 @@
+package appwizard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Answers contains preset answers keyed by question key, like DB_CONNECTION
+type Answers map[string]string
+
+// LoadAnswers reads an answers file, the format is chosen by the extension (.yaml, .yml or .json)
+func LoadAnswers(fileName string) (Answers, error) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var answers Answers
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		answers, err = parseYAMLAnswers(content)
+	case ".json":
+		answers, err = parseJSONAnswers(content)
+	default:
+		return nil, fmt.Errorf("unsupported answers file format '%s', use .yaml or .json", filepath.Ext(fileName))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse answers file %s: %w", fileName, err)
+	}
+
+	return answers, nil
+}
+
+// parseYAMLAnswers keeps the values as they are written, 0123 is not read as an octal number and 1.10 stays 1.10
+func parseYAMLAnswers(content []byte) (Answers, error) {
+	values := make(map[string]yaml.Node)
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+
+	answers := make(Answers, len(values))
+	for key, value := range values {
+		if value.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: answer %s must be a single value", value.Line, key)
+		}
+
+		if value.Tag == "!!null" {
+			continue
+		}
+		answers[key] = value.Value
+	}
+
+	return answers, nil
+}
+
+// parseJSONAnswers keeps the numbers as they are written, like the YAML answers
+func parseJSONAnswers(content []byte) (Answers, error) {
+	values := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(content, &values); err != nil {
+		return nil, err
+	}
+
+	answers := make(Answers, len(values))
+	for key, value := range values {
+		text := strings.TrimSpace(string(value))
+		switch {
+		case text == "null":
+			continue
+		case strings.HasPrefix(text, "\""):
+			var answer string
+			if err := json.Unmarshal(value, &answer); err != nil {
+				return nil, err
+			}
+			answers[key] = answer
+		case strings.HasPrefix(text, "{") || strings.HasPrefix(text, "["):
+			return nil, fmt.Errorf("answer %s must be a single value", key)
+		default:
+			answers[key] = text
+		}
+	}
+
+	return answers, nil
+}
@@ -0,0 +1,67 @@
+package appwizard
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAnswersKeepsValues(t *testing.T) {
+	// JSON numbers have no leading zeros, so they are strings there
+	files := map[string]string{
+		"answers.yaml": "DB_PASSWORD: 0123\nDB_PORT: 03306\nAPP_VERSION: 1.10\nDEBUG: yes\nDB_HOST: ~\n",
+		"answers.json": `{"DB_PASSWORD": "0123", "DB_PORT": "03306", "APP_VERSION": 1.10, "DEBUG": "yes", "DB_HOST": null}`,
+	}
+
+	for name, content := range files {
+		fileName := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(fileName, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		answers, err := LoadAnswers(fileName)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		want := Answers{"DB_PASSWORD": "0123", "DB_PORT": "03306", "APP_VERSION": "1.10", "DEBUG": "yes"}
+		if len(answers) != len(want) {
+			t.Errorf("%s: answers %v, want %v", name, answers, want)
+		}
+		for key, value := range want {
+			if answers[key] != value {
+				t.Errorf("%s: %s = %q, want %q", name, key, answers[key], value)
+			}
+		}
+	}
+}
+
+func TestWizardRejectsUnknownAnswers(t *testing.T) {
+	options := Options{Answers: Answers{"DB_CONECTION": "pgsql"}, NonInteractive: true}
+	_, _, _, err := Wizard("", options)
+	if err == nil || !strings.Contains(err.Error(), "no question for DB_CONECTION") {
+		t.Errorf("Wizard() = %v, want the unknown answer reported", err)
+	}
+}
+
+func TestWizardListsUnansweredMandatoryQuestions(t *testing.T) {
+	options := Options{NonInteractive: true, Answers: Answers{
+		"DB_CONNECTION": "mysql", "DB_USERNAME": "", "SESSION_STORAGE": "file", "LOGGER_STORAGE": "file", "CACHE_STORAGE": "file",
+	}}
+
+	_, _, _, err := Wizard("", options)
+	if err == nil || !strings.Contains(err.Error(), "unanswered mandatory questions: DB_DATABASE, DB_USERNAME, DB_PASSWORD") {
+		t.Errorf("Wizard() = %v, want the unanswered MySQL questions listed", err)
+	}
+}
+
+func TestWizardRejectsInvalidPresetBeforeAsking(t *testing.T) {
+	// nothing is answered on stdin, a question asked would fail with ErrNoInput
+	options := Options{Answers: Answers{"DB_CONNECTION": "oracle"}}
+
+	_, _, _, err := Wizard("", options)
+	if err == nil || !strings.Contains(err.Error(), "DB_CONNECTION=oracle") {
+		t.Errorf("Wizard() = %v, want the invalid answer reported", err)
+	}
+}
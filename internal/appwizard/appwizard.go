@@ -1,9 +1,11 @@
 package appwizard
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/olbrichattila/creategofra/internal/specio"
@@ -18,107 +20,436 @@ type EnvData struct {
 type answer struct {
 	value        string
 	nextQuestion *question
+	// branches are walked one after the other when the answer selects several options
+	branches []*question
 }
-type answers map[string]answer
+
+// option is a choice of a selection, it may branch to its own next question
+type option struct {
+	label        string
+	value        string
+	nextQuestion *question
+}
+
 type question struct {
 	key           string
+	answerKey     string
+	flag          string
 	prompt        string
 	defaultAnswer string
 	mandatory     bool
-	answers       answers
+	secret        bool
+	kind          questionType
+	min           int
+	max           int
+	validate      func(value string) error
+	options       []option
 	nextQuestion  *question
 }
 
-func Wizard(envFileName string) ([]EnvData, []string) {
-	envContent := getEnvContent(envFileName)
+// Options controls where the wizard takes its answers from
+type Options struct {
+	// Answers are preset answers, the questions they answer are not asked
+	Answers Answers
+	// NonInteractive never prompts, missing answers fall back to their defaults
+	NonInteractive bool
+	// EscConfirms makes Esc confirm an answer, by default Esc aborts the wizard like Ctrl-C
+	EscConfirms bool
+	// Record receives the accepted answers by name, including the answers without .env key, secrets are left out
+	Record func(answers map[string]string)
+}
+
+type wizard struct {
+	envContent string
+	options    Options
+	missing    []string
+	invalid    []string
+}
+
+func newWizard(envContent string, options Options) *wizard {
+	specio.EscAborts = !options.EscConfirms
+
+	return &wizard{envContent: envContent, options: options}
+}
 
-	responses := processQuestions(envContent, appUrlQuestion)
+// Wizard asks the questions, starting from the values of the current .env content, and returns the answers,
+// the selected storages and the merged .env content
+func Wizard(envContent string, options Options) ([]EnvData, []string, string, error) {
+	w := newWizard(envContent, options)
+	if err := w.checkAnswers(); err != nil {
+		return nil, nil, "", err
+	}
 
-	storages := getStorages(responses)
-	for _, storageName := range storages {
-		if storageQuestion, ok := storageQuestionMap[storageName]; ok {
-			if storageQuestion == nil {
-				continue
+	responses, err := w.walk(func(responses []EnvData) []*question {
+		roots := []*question{graph.start}
+		for _, storageName := range getStorages(responses) {
+			if storageQuestion := graph.storages[storageName]; storageQuestion != nil {
+				roots = append(roots, storageQuestion)
 			}
-			responses = append(responses, processQuestions(envContent, *storageQuestion)...)
 		}
+		roots = append(roots, graph.roots...)
+
+		return roots
+	})
+	if err != nil {
+		return nil, nil, "", err
 	}
 
-	envStr := mergeEnv(envContent, responses)
-	saveEnvContent(envFileName, envStr)
+	if err := w.err(); err != nil {
+		return nil, nil, "", err
+	}
 
-	return responses, storages
+	return responses, getStorages(responses), mergeEnv(w.envContent, responses), nil
 }
 
-func processQuestions(envContent string, q question) []EnvData {
-	responses := make([]EnvData, 0)
-	currentQuestion := q
+// ProjectType returns the project template selected by the user, blank or regapp
+func ProjectType(options Options) (string, error) {
+	w := newWizard("", options)
 	for {
-		currentValue := lookupValue(envContent, currentQuestion.key)
-		if currentValue == "" {
-			currentValue = currentQuestion.defaultAnswer
+		answer, err := w.answer(graph.project, "")
+		if errors.Is(err, specio.ErrBack) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		if err := w.err(); err != nil {
+			return "", err
+		}
+
+		return answer.value, nil
+	}
+}
+
+// checkAnswers checks the preset answers before any question is asked, the answers which match no question
+// are invalid too, they are probably misspelled
+func (w *wizard) checkAnswers() error {
+	byName := make(map[string][]*question)
+	for q := range graph.reachable() {
+		byName[q.name()] = append(byName[q.name()], q)
+	}
+
+	names := make([]string, 0, len(w.options.Answers))
+	for name := range w.options.Answers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	unknown := make([]string, 0)
+	for _, name := range names {
+		named := byName[name]
+		if name == "" || len(named) == 0 {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		// questions sharing a key in different branches may differ, the answer has to suit one of them
+		var err error
+		for _, q := range named {
+			if _, err = q.preset(w.options.Answers[name]); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			w.invalid = append(w.invalid, err.Error())
+		}
+	}
+
+	if len(unknown) > 0 {
+		w.invalid = append(w.invalid, "no question for "+strings.Join(unknown, ", "))
+	}
+
+	return w.err()
+}
+
+// PresetProjectType returns the project type given by the value of its flag, or else by the preset answers,
+// so the template can be opened before the questions are asked
+func PresetProjectType(answers Answers, flagValue string) (string, bool) {
+	value, ok := answers[graph.project.name()]
+	if flagValue != "" {
+		value, ok = flagValue, true
+	}
+
+	if !ok {
+		return "", false
+	}
+
+	preset, ok := graph.project.resolve(value)
+	if !ok {
+		return "", false
+	}
+
+	return preset.value, true
+}
+
+// answer takes the answer from the preset answers, then from the user, or in
+// non-interactive mode from the current value, recording what is missing
+func (w *wizard) answer(q *question, currentValue string) (*answer, error) {
+	if value, ok := w.options.Answers[q.name()]; ok {
+		if value == "" && q.mandatory {
+			w.missing = append(w.missing, q.name())
+			return w.skip(q), nil
+		}
+
+		presetAnswer, err := q.preset(value)
+		if err != nil {
+			w.invalid = append(w.invalid, err.Error())
+			return w.skip(q), nil
+		}
+
+		return presetAnswer, nil
+	}
+
+	if !w.options.NonInteractive {
+		return w.ask(q, currentValue)
+	}
+
+	if err := q.check(currentValue); err != nil {
+		w.invalid = append(w.invalid, err.Error())
+		return w.skip(q), nil
+	}
+
+	if defaultAnswer, ok := q.resolve(currentValue); ok && (currentValue != "" || !q.mandatory) {
+		return defaultAnswer, nil
+	}
+
+	w.missing = append(w.missing, q.name())
+	return w.skip(q), nil
+}
+
+// asks tells if the question is asked from the user, rather than answered by a preset answer or a default
+func (w *wizard) asks(q *question) bool {
+	_, preset := w.options.Answers[q.name()]
+
+	return !preset && !w.options.NonInteractive
+}
+
+func (w *wizard) ask(q *question, currentValue string) (*answer, error) {
+	answer, err := selection(*q, currentValue)
+	fmt.Fprintln(specio.Output, "")
+
+	return answer, err
+}
+
+// record passes the answers of the steps to Options.Record, the answers of secret questions are left out
+func (w *wizard) record(steps []step) {
+	if w.options.Record == nil {
+		return
+	}
+
+	answers := make(map[string]string)
+	for _, s := range steps {
+		if s.question.name() != "" && !s.question.secret && s.answer.value != "" {
+			answers[s.question.name()] = s.answer.value
+		}
+	}
+	w.options.Record(answers)
+}
+
+// skip continues with the question following q without answering it, so the rest of the graph is still checked
+func (w *wizard) skip(q *question) *answer {
+	return &answer{nextQuestion: q.nextQuestion}
+}
+
+func (w *wizard) err() error {
+	problems := make([]string, 0)
+	if len(w.missing) > 0 {
+		problems = append(problems, "unanswered mandatory questions: "+strings.Join(w.missing, ", "))
+	}
+
+	if len(w.invalid) > 0 {
+		problems = append(problems, "invalid answers: "+strings.Join(w.invalid, "; "))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(problems, "\n"))
+}
+
+// name is the identifier of the question in answers files
+func (q question) name() string {
+	if q.key != "" {
+		return q.key
+	}
+
+	return q.answerKey
+}
+
+// display returns the value as it can be shown on the screen, secrets are redacted
+func (q question) display(value string) string {
+	if q.secret && value != "" {
+		return "********"
+	}
+
+	return value
+}
+
+// preset checks a preset answer and turns it to an answer
+func (q question) preset(value string) (*answer, error) {
+	if err := q.check(value); err != nil {
+		return nil, err
+	}
+
+	presetAnswer, ok := q.resolve(value)
+	if !ok {
+		return nil, fmt.Errorf("%s=%s (allowed: %s)", q.name(), value, strings.Join(q.allowedValues(), ", "))
+	}
+
+	return presetAnswer, nil
+}
+
+// resolve turns a value to an answer, an option can be given by its number or its value
+func (q question) resolve(value string) (*answer, bool) {
+	if len(q.options) == 0 {
+		return &answer{value: value, nextQuestion: q.nextQuestion}, true
+	}
+
+	if q.kind == multiSelectType {
+		checked, ok := q.checked(value)
+		if !ok {
+			return nil, false
 		}
-		answer := selection(currentQuestion, currentValue)
-		fmt.Println("")
-		if answer == nil {
-			break
+
+		return q.checkedAnswer(checked), true
+	}
+
+	i := q.optionIndex(value)
+	if i < 0 {
+		return nil, false
+	}
+
+	selected := q.options[i]
+	if selected.nextQuestion == nil {
+		return &answer{value: selected.value, nextQuestion: q.nextQuestion}, true
+	}
+
+	return &answer{value: selected.value, nextQuestion: selected.nextQuestion}, true
+}
+
+// optionIndex returns the index of the option with the value, or numbered by the value starting from 1, or -1
+func (q question) optionIndex(value string) int {
+	for i, o := range q.options {
+		if o.value == value {
+			return i
+		}
+	}
+
+	if number, err := strconv.Atoi(value); err == nil && number > 0 && number <= len(q.options) {
+		return number - 1
+	}
+
+	return -1
+}
+
+// checked returns the options of a multiselect listed in a comma separated value, by their values or numbers
+func (q question) checked(value string) ([]bool, bool) {
+	checked := make([]bool, len(q.options))
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || item == "none" {
+			continue
 		}
 
-		if currentQuestion.key != "" && answer.value != "" {
-			responses = append(responses, EnvData{Key: currentQuestion.key, Value: answer.value})
+		i := q.optionIndex(item)
+		if i < 0 {
+			return nil, false
 		}
+		checked[i] = true
+	}
 
-		if answer.nextQuestion != nil {
-			currentQuestion = *answer.nextQuestion
+	return checked, true
+}
+
+// checkedAnswer lists the values of the checked options, the branches of the options are followed
+// in the order of the options, then the next question of the multiselect
+func (q question) checkedAnswer(checked []bool) *answer {
+	values := make([]string, 0)
+	branches := make([]*question, 0)
+	for i, o := range q.options {
+		if !checked[i] {
 			continue
 		}
 
-		break
+		values = append(values, o.value)
+		if o.nextQuestion != nil {
+			branches = append(branches, o.nextQuestion)
+		}
+	}
+
+	if q.nextQuestion != nil {
+		branches = append(branches, q.nextQuestion)
+	}
+
+	return &answer{value: strings.Join(values, ","), branches: branches}
+}
+
+func (q question) allowedValues() []string {
+	values := make([]string, 0, len(q.options))
+	for _, o := range q.options {
+		values = append(values, o.value)
+	}
+
+	return values
+}
+
+func (q question) labels() []string {
+	labels := make([]string, 0, len(q.options))
+	for _, o := range q.options {
+		labels = append(labels, o.label)
 	}
 
-	return responses
+	return labels
 }
 
-func selection(q question, currentValue string) *answer {
-	prompt := ""
-	if len(q.answers) > 0 {
-		fmt.Println(q.prompt)
-		prompt = "Please choose: "
-	} else {
-		prompt = q.prompt + ": "
+func selection(q question, currentValue string) (*answer, error) {
+	if q.kind == multiSelectType {
+		checked, _ := q.checked(currentValue)
+		checked, err := specio.MultiSelect(q.prompt+":", q.labels(), checked)
+		if err != nil {
+			return nil, err
+		}
+
+		return q.checkedAnswer(checked), nil
+	}
+
+	if len(q.options) > 0 {
+		selected, err := specio.Select(q.prompt+":", q.labels(), q.optionIndex(currentValue))
+		if err != nil {
+			return nil, err
+		}
+
+		selectedAnswer, _ := q.resolve(q.options[selected].value)
+		return selectedAnswer, nil
+	}
+
+	prompt := q.prompt + ": "
+	input := specio.Input
+	if q.secret {
+		input = specio.Secret
 	}
 
 	for {
-		response := ""
-		if len(q.answers) == 0 {
-			response = specio.Input(prompt, currentValue)
-		} else {
-			resolvedAnswer := resolveAnswer(q.answers, currentValue)
-			response = specio.Input(prompt, resolvedAnswer)
+		response, err := input(prompt, currentValue)
+		if err != nil {
+			return nil, err
 		}
 
 		if response == "" && q.mandatory {
-			if len(q.answers) == 0 {
-				fmt.Println("Please provide a value")
-			} else {
-				fmt.Println("Please select an option")
-			}
+			fmt.Fprintln(specio.Output, "\nPlease provide a value")
 			continue
 		}
 
-		if len(q.answers) > 0 {
-			if selected, ok := q.answers[response]; ok {
-				if selected.nextQuestion == nil && q.nextQuestion != nil {
-					return &answer{value: selected.value, nextQuestion: q.nextQuestion}
-				}
-				return &selected
-			}
-
-			fmt.Println("invalid selection")
+		if err := q.check(response); err != nil {
+			fmt.Fprintln(specio.Output, "\n"+err.Error())
+			currentValue = response
 			continue
 		}
 
-		return &answer{value: response, nextQuestion: q.nextQuestion}
+		return &answer{value: response, nextQuestion: q.nextQuestion}, nil
 	}
 }
 
@@ -165,29 +496,6 @@ func lookupValue(envContent, keyValue string) string {
 	return ""
 }
 
-func getEnvContent(envFileName string) string {
-	content, err := os.ReadFile(envFileName)
-	if err != nil {
-		return ""
-	}
-
-	return string(content)
-}
-
-func saveEnvContent(envFileName, fileContent string) error {
-	return os.WriteFile(envFileName, []byte(fileContent), 0644)
-}
-
-func resolveAnswer(a answers, value string) string {
-	for key, answer := range a {
-		if answer.value == value {
-			return key
-		}
-	}
-
-	return ""
-}
-
 func getStorages(data []EnvData) []string {
 	re := regexp.MustCompile(`.*_STORAGE`)
 
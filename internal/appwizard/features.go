@@ -0,0 +1,63 @@
+package appwizard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Features returns the names of the features which can be added to an existing project
+func Features() []string {
+	return sortedNames(graph.features)
+}
+
+// AddFeature asks the questions of a feature and returns the whole .env content with the answers merged,
+// as env data, the used storages and the .env file content
+func AddFeature(envContent, feature string, options Options) ([]EnvData, []string, string, error) {
+	featureQuestion, ok := graph.features[feature]
+	if !ok {
+		return nil, nil, "", fmt.Errorf("unknown feature '%s', available: %s", feature, strings.Join(Features(), ", "))
+	}
+
+	w := newWizard(envContent, options)
+	if err := w.checkAnswers(); err != nil {
+		return nil, nil, "", err
+	}
+	responses, err := w.walk(func([]EnvData) []*question { return []*question{featureQuestion} })
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if err := w.err(); err != nil {
+		return nil, nil, "", err
+	}
+
+	envStr := mergeEnv(w.envContent, responses)
+	envData, storages := ParseEnv(envStr)
+
+	return envData, storages, envStr, nil
+}
+
+// ParseEnv returns the values of a .env file content and the storages it uses
+func ParseEnv(envContent string) ([]EnvData, []string) {
+	envData := parseEnv(envContent)
+
+	return envData, getStorages(envData)
+}
+
+func parseEnv(envContent string) []EnvData {
+	envData := make([]EnvData, 0)
+	for _, line := range strings.Split(envContent, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		envData = append(envData, EnvData{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value)})
+	}
+
+	return envData
+}
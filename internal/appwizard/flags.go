@@ -0,0 +1,87 @@
+package appwizard
+
+import (
+	"sort"
+	"strings"
+)
+
+// Flag describes the command-line flag answering a question
+type Flag struct {
+	Name     string
+	Key      string
+	Usage    string
+	Type     string
+	Defaults []string
+	Values   []string
+}
+
+// Flags returns a flag for every question, questions sharing a key in different branches share a flag
+func Flags() []Flag {
+	flags := make([]Flag, 0)
+	index := make(map[string]int)
+	visited := make(map[*question]bool)
+
+	var walk func(q *question)
+	walk = func(q *question) {
+		if q == nil || visited[q] {
+			return
+		}
+		visited[q] = true
+
+		if q.name() != "" {
+			i, ok := index[q.name()]
+			if !ok {
+				i = len(flags)
+				index[q.name()] = i
+				flags = append(flags, Flag{
+					Name:   q.flagName(),
+					Key:    q.name(),
+					Usage:  q.prompt,
+					Type:   q.typeName(),
+					Values: q.allowedValues(),
+				})
+			}
+			if q.defaultAnswer != "" && !sliceContains(flags[i].Defaults, q.defaultAnswer) {
+				flags[i].Defaults = append(flags[i].Defaults, q.defaultAnswer)
+			}
+		}
+
+		for _, o := range q.options {
+			walk(o.nextQuestion)
+		}
+		walk(q.nextQuestion)
+	}
+
+	walk(graph.project)
+	walk(graph.start)
+	for _, storageName := range sortedNames(graph.storages) {
+		walk(graph.storages[storageName])
+	}
+	for _, root := range graph.roots {
+		walk(root)
+	}
+	for _, feature := range sortedNames(graph.features) {
+		walk(graph.features[feature])
+	}
+
+	return flags
+}
+
+// flagName is the name of the command-line flag, derived from the key unless set explicitly
+func (q question) flagName() string {
+	if q.flag != "" {
+		return q.flag
+	}
+
+	return strings.ReplaceAll(strings.ToLower(q.name()), "_", "-")
+}
+
+func sortedNames(questions map[string]*question) []string {
+	names := make([]string, 0, len(questions))
+	for name := range questions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,548 @@
+package appwizard
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed files/questions.yaml
+var defaultQuestionsFile []byte
+
+// questionFile is the declarative form of the question graph, see files/questions.yaml
+type questionFile struct {
+	Project   string                  `yaml:"project" json:"project"`
+	Start     string                  `yaml:"start" json:"start"`
+	Storages  map[string]string       `yaml:"storages" json:"storages"`
+	Features  map[string]string       `yaml:"features" json:"features"`
+	Roots     []string                `yaml:"roots" json:"roots"`
+	Questions map[string]questionSpec `yaml:"questions" json:"questions"`
+}
+
+type questionSpec struct {
+	Key       string       `yaml:"key" json:"key"`
+	AnswerKey string       `yaml:"answerKey" json:"answerKey"`
+	Flag      string       `yaml:"flag" json:"flag"`
+	Prompt    string       `yaml:"prompt" json:"prompt"`
+	Default   string       `yaml:"default" json:"default"`
+	Mandatory bool         `yaml:"mandatory" json:"mandatory"`
+	Secret    bool         `yaml:"secret" json:"secret"`
+	Type      string       `yaml:"type" json:"type"`
+	Min       int          `yaml:"min" json:"min"`
+	Max       int          `yaml:"max" json:"max"`
+	OneOf     []string     `yaml:"oneOf" json:"oneOf"`
+	Pattern   string       `yaml:"pattern" json:"pattern"`
+	Options   []optionSpec `yaml:"options" json:"options"`
+	Next      string       `yaml:"next" json:"next"`
+}
+
+type optionSpec struct {
+	Label string `yaml:"label" json:"label"`
+	Value string `yaml:"value" json:"value"`
+	Next  string `yaml:"next" json:"next"`
+}
+
+// questionGraph is the linked question graph built from a question file
+type questionGraph struct {
+	project  *question
+	start    *question
+	storages map[string]*question
+	features map[string]*question
+	// roots are asked after the storage questions, in order
+	roots []*question
+}
+
+var questionTypes = map[string]questionType{
+	"":            stringType,
+	"string":      stringType,
+	"int":         intType,
+	"port":        portType,
+	"url":         urlType,
+	"hostname":    hostnameType,
+	"path":        pathType,
+	"email":       emailType,
+	"bool":        boolType,
+	"multiselect": multiSelectType,
+}
+
+var defaultQuestions = mustParseQuestionFile(defaultQuestionsFile)
+
+// questions are the embedded questions extended with the loaded ones, the graph is built from them
+var questions = defaultQuestions
+
+var graph = mustBuild(questions)
+
+// LoadQuestions extends the embedded questions with the questions of a YAML or JSON file, questions with the
+// same id are replaced, the storages, features and roots are merged and the project and start questions are
+// overridden when set. New questions no other question leads to are asked after the others, in the order of the file.
+func LoadQuestions(fileName string) error {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	var overlay questionFile
+	var order []string
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yaml", ".yml":
+		overlay, err = parseQuestionFile(content)
+		order = questionOrder(content)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&overlay)
+		order = jsonQuestionOrder(content)
+	default:
+		return fmt.Errorf("unsupported questions file format '%s', use .yaml or .json", filepath.Ext(fileName))
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot parse questions file %s: %w", fileName, err)
+	}
+
+	return extend(fileName, overlay, order)
+}
+
+// ExtendQuestions extends the questions like LoadQuestions with the YAML content of a questions file,
+// the source names the content in the errors
+func ExtendQuestions(source string, content []byte) error {
+	overlay, err := parseQuestionFile(content)
+	if err != nil {
+		return fmt.Errorf("cannot parse questions of %s: %w", source, err)
+	}
+
+	return extend(source, overlay, questionOrder(content))
+}
+
+// extend merges the overlay into the questions, the new questions no question leads to become roots in the given order
+func extend(source string, overlay questionFile, order []string) error {
+	merged := questions.merge(overlay)
+	referenced := merged.referenced()
+	for _, id := range order {
+		if _, existing := questions.Questions[id]; !existing && !referenced[id] {
+			merged.Roots = append(merged.Roots, id)
+			referenced[id] = true
+		}
+	}
+
+	mergedGraph, err := merged.build()
+	if err != nil {
+		return fmt.Errorf("invalid questions of %s: %w", source, err)
+	}
+
+	questions = merged
+	graph = mergedGraph
+
+	return nil
+}
+
+func parseQuestionFile(content []byte) (questionFile, error) {
+	var file questionFile
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&file)
+
+	return file, err
+}
+
+// questionOrder returns the ids of the questions in the order they are written in a YAML questions file
+func questionOrder(content []byte) []string {
+	var file struct {
+		Questions yaml.Node `yaml:"questions"`
+	}
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil
+	}
+
+	ids := make([]string, 0, len(file.Questions.Content)/2)
+	for i := 0; i+1 < len(file.Questions.Content); i += 2 {
+		ids = append(ids, file.Questions.Content[i].Value)
+	}
+
+	return ids
+}
+
+// jsonQuestionOrder returns the ids of the questions in the order they are written in a JSON questions file
+func jsonQuestionOrder(content []byte) []string {
+	var file struct {
+		Questions json.RawMessage `json:"questions"`
+	}
+	if err := json.Unmarshal(content, &file); err != nil || len(file.Questions) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(file.Questions))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	ids := make([]string, 0)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return ids
+		}
+
+		id, ok := token.(string)
+		if !ok {
+			return ids
+		}
+		ids = append(ids, id)
+
+		var spec json.RawMessage
+		if err := decoder.Decode(&spec); err != nil {
+			return ids
+		}
+	}
+
+	return ids
+}
+
+func sortedIds(specs map[string]questionSpec) []string {
+	ids := make([]string, 0, len(specs))
+	for id := range specs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+func mustParseQuestionFile(content []byte) questionFile {
+	file, err := parseQuestionFile(content)
+	if err != nil {
+		panic("invalid embedded questions file: " + err.Error())
+	}
+
+	return file
+}
+
+func mustBuild(file questionFile) *questionGraph {
+	g, err := file.build()
+	if err != nil {
+		panic("invalid embedded questions file: " + err.Error())
+	}
+
+	return g
+}
+
+func (f questionFile) merge(overlay questionFile) questionFile {
+	merged := questionFile{
+		Project:   f.Project,
+		Start:     f.Start,
+		Storages:  make(map[string]string),
+		Features:  make(map[string]string),
+		Questions: make(map[string]questionSpec),
+	}
+
+	if overlay.Project != "" {
+		merged.Project = overlay.Project
+	}
+
+	if overlay.Start != "" {
+		merged.Start = overlay.Start
+	}
+
+	for _, source := range []questionFile{f, overlay} {
+		for _, id := range source.Roots {
+			if !sliceContains(merged.Roots, id) {
+				merged.Roots = append(merged.Roots, id)
+			}
+		}
+		for name, id := range source.Storages {
+			merged.Storages[name] = id
+		}
+		for name, id := range source.Features {
+			merged.Features[name] = id
+		}
+		for id, spec := range source.Questions {
+			merged.Questions[id] = spec
+		}
+	}
+
+	return merged
+}
+
+// referenced returns the ids of the questions another question, or the project, start, storages, features
+// or roots lead to
+func (f questionFile) referenced() map[string]bool {
+	referenced := map[string]bool{f.Project: true, f.Start: true}
+	for _, ids := range []map[string]string{f.Storages, f.Features} {
+		for _, id := range ids {
+			referenced[id] = true
+		}
+	}
+	for _, id := range f.Roots {
+		referenced[id] = true
+	}
+	for _, spec := range f.Questions {
+		referenced[spec.Next] = true
+		for _, optionSpec := range spec.Options {
+			referenced[optionSpec.Next] = true
+		}
+	}
+
+	return referenced
+}
+
+// build links the questions, it fails on unknown types, invalid rules, dangling references, cycles and
+// questions which are never asked
+func (f questionFile) build() (*questionGraph, error) {
+	problems := make([]string, 0)
+	questions := make(map[string]*question, len(f.Questions))
+	for id, spec := range f.Questions {
+		q, err := spec.question()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("question '%s': %v", id, err))
+			continue
+		}
+		questions[id] = q
+	}
+
+	resolve := func(from, id string) *question {
+		if id == "" {
+			return nil
+		}
+		q, ok := questions[id]
+		if !ok {
+			if _, defined := f.Questions[id]; !defined {
+				problems = append(problems, fmt.Sprintf("%s refers to unknown question '%s'", from, id))
+			}
+		}
+		return q
+	}
+
+	for id, spec := range f.Questions {
+		q, ok := questions[id]
+		if !ok {
+			continue
+		}
+
+		q.nextQuestion = resolve(fmt.Sprintf("question '%s'", id), spec.Next)
+		for i, optionSpec := range spec.Options {
+			q.options[i].nextQuestion = resolve(fmt.Sprintf("option '%s' of question '%s'", optionSpec.Value, id), optionSpec.Next)
+		}
+	}
+
+	g := &questionGraph{storages: make(map[string]*question), features: make(map[string]*question)}
+	if f.Start == "" {
+		problems = append(problems, "start question is not set")
+	}
+	if f.Project == "" {
+		problems = append(problems, "project question is not set")
+	}
+	g.start = resolve("start", f.Start)
+	g.project = resolve("project", f.Project)
+	for name, id := range f.Storages {
+		g.storages[name] = resolve(fmt.Sprintf("storage '%s'", name), id)
+	}
+	for name, id := range f.Features {
+		g.features[name] = resolve(fmt.Sprintf("feature '%s'", name), id)
+	}
+	for _, id := range f.Roots {
+		if root := resolve("roots", id); root != nil {
+			g.roots = append(g.roots, root)
+		}
+	}
+
+	flagIds := make(map[string]string)
+	for _, id := range sortedIds(f.Questions) {
+		q, ok := questions[id]
+		if !ok || q.name() == "" {
+			continue
+		}
+
+		// questions sharing a key in different branches share their flag
+		if other, taken := flagIds[q.flagName()]; taken && questions[other].name() != q.name() {
+			problems = append(problems, fmt.Sprintf("questions '%s' and '%s' have the same flag --%s", other, id, q.flagName()))
+			continue
+		}
+		flagIds[q.flagName()] = id
+	}
+
+	reachable := g.reachable()
+	for id, q := range questions {
+		if !reachable[q] {
+			problems = append(problems, fmt.Sprintf("question '%s' is never asked, link it with next or list it in roots", id))
+		}
+	}
+
+	if cycle := findCycle(questions); cycle != "" {
+		problems = append(problems, "questions form a cycle: "+cycle)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+
+	return g, nil
+}
+
+func (s questionSpec) question() (*question, error) {
+	kind, ok := questionTypes[strings.ToLower(s.Type)]
+	if !ok {
+		return nil, fmt.Errorf("unknown type '%s'", s.Type)
+	}
+
+	if s.Key == "" && s.AnswerKey == "" && len(s.Options) == 0 {
+		return nil, fmt.Errorf("key or answerKey is required")
+	}
+
+	if kind == multiSelectType && len(s.Options) == 0 {
+		return nil, fmt.Errorf("multiselect needs options")
+	}
+
+	q := &question{
+		key:           s.Key,
+		answerKey:     s.AnswerKey,
+		flag:          s.Flag,
+		prompt:        s.Prompt,
+		defaultAnswer: s.Default,
+		mandatory:     s.Mandatory,
+		secret:        s.Secret,
+		kind:          kind,
+		min:           s.Min,
+		max:           s.Max,
+	}
+
+	for _, optionSpec := range s.Options {
+		if optionSpec.Value == "" {
+			return nil, fmt.Errorf("option '%s' has no value", optionSpec.Label)
+		}
+
+		label := optionSpec.Label
+		if label == "" {
+			label = optionSpec.Value
+		}
+		q.options = append(q.options, option{label: label, value: optionSpec.Value})
+	}
+
+	validators := make([]func(string) error, 0)
+	if len(s.OneOf) > 0 {
+		validators = append(validators, oneOf(s.OneOf...))
+	}
+
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+		validators = append(validators, matches(re))
+	}
+
+	if len(validators) > 0 {
+		q.validate = func(value string) error {
+			for _, validator := range validators {
+				if err := validator(value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	return q, nil
+}
+
+// reachable returns the questions which can be asked, starting from the project, start, storage,
+// feature and root questions
+func (g *questionGraph) reachable() map[*question]bool {
+	reached := make(map[*question]bool)
+	var visit func(q *question)
+	visit = func(q *question) {
+		if q == nil || reached[q] {
+			return
+		}
+		reached[q] = true
+		visit(q.nextQuestion)
+		for _, o := range q.options {
+			visit(o.nextQuestion)
+		}
+	}
+
+	visit(g.project)
+	visit(g.start)
+	for _, q := range g.storages {
+		visit(q)
+	}
+	for _, q := range g.features {
+		visit(q)
+	}
+	for _, q := range g.roots {
+		visit(q)
+	}
+
+	return reached
+}
+
+// findCycle returns the ids of a cycle in the question graph, or an empty string if there is none
+func findCycle(questions map[string]*question) string {
+	ids := make(map[*question]string, len(questions))
+	for id, q := range questions {
+		ids[q] = id
+	}
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[*question]int)
+	path := make([]string, 0)
+
+	var visit func(q *question) string
+	visit = func(q *question) string {
+		if q == nil || state[q] == done {
+			return ""
+		}
+
+		if state[q] == inProgress {
+			start := 0
+			for i, id := range path {
+				if id == ids[q] {
+					start = i
+				}
+			}
+			return strings.Join(append(path[start:], ids[q]), " -> ")
+		}
+
+		state[q] = inProgress
+		path = append(path, ids[q])
+		next := []*question{q.nextQuestion}
+		for _, o := range q.options {
+			next = append(next, o.nextQuestion)
+		}
+		for _, n := range next {
+			if cycle := visit(n); cycle != "" {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[q] = done
+
+		return ""
+	}
+
+	sortedIds := make([]string, 0, len(questions))
+	for id := range questions {
+		sortedIds = append(sortedIds, id)
+	}
+	sort.Strings(sortedIds)
+
+	for _, id := range sortedIds {
+		if cycle := visit(questions[id]); cycle != "" {
+			return cycle
+		}
+	}
+
+	return ""
+}
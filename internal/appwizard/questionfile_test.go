@@ -0,0 +1,102 @@
+package appwizard
+
+import (
+	"strings"
+	"testing"
+)
+
+// restoreQuestions puts back the embedded questions once the test extended them
+func restoreQuestions(t *testing.T) {
+	saved, savedGraph := questions, graph
+	t.Cleanup(func() {
+		questions, graph = saved, savedGraph
+	})
+}
+
+func TestExtendedQuestionsAreAsked(t *testing.T) {
+	restoreQuestions(t)
+	manifestQuestions := `
+questions:
+  app-title:
+    key: APP_TITLE
+    prompt: Title of the application
+    default: My App
+  app-owner:
+    key: APP_OWNER
+    prompt: Owner
+`
+	if err := ExtendQuestions("template starter", []byte(manifestQuestions)); err != nil {
+		t.Fatal(err)
+	}
+
+	flagNames := make([]string, 0)
+	for _, flag := range Flags() {
+		flagNames = append(flagNames, flag.Name)
+	}
+	for _, name := range []string{"app-title", "app-owner"} {
+		if !sliceContains(flagNames, name) {
+			t.Errorf("no flag --%s in %v", name, flagNames)
+		}
+	}
+
+	options := Options{NonInteractive: true, Answers: Answers{
+		"DB_CONNECTION": "sqlite", "SESSION_STORAGE": "file", "LOGGER_STORAGE": "file", "CACHE_STORAGE": "file",
+		"APP_OWNER": "acme",
+	}}
+	responses, _, _, err := Wizard("", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if title := valueOf(responses, "APP_TITLE"); title != "My App" {
+		t.Errorf("APP_TITLE = %q, want the default", title)
+	}
+	if owner := valueOf(responses, "APP_OWNER"); owner != "acme" {
+		t.Errorf("APP_OWNER = %q, want the preset answer", owner)
+	}
+}
+
+func TestBuildRejectsQuestionsNeverAsked(t *testing.T) {
+	file := questionFile{
+		Project: "type",
+		Start:   "first",
+		Questions: map[string]questionSpec{
+			"type":   {AnswerKey: "PROJECT_TYPE", Options: []optionSpec{{Value: "blank"}}},
+			"first":  {Key: "FIRST"},
+			"orphan": {Key: "ORPHAN"},
+		},
+	}
+
+	if _, err := file.build(); err == nil || !strings.Contains(err.Error(), "'orphan' is never asked") {
+		t.Errorf("build() = %v, want the orphan question reported", err)
+	}
+
+	file.Roots = []string{"orphan"}
+	if _, err := file.build(); err != nil {
+		t.Errorf("build() = %v, want the root accepted", err)
+	}
+}
+
+func TestBuildRejectsSameFlag(t *testing.T) {
+	file := questionFile{
+		Project: "type",
+		Start:   "first",
+		Questions: map[string]questionSpec{
+			"type":  {AnswerKey: "PROJECT_TYPE", Flag: "type", Options: []optionSpec{{Value: "blank"}}},
+			"first": {Key: "TYPE", Next: "again"},
+			"again": {Key: "TYPE"},
+		},
+	}
+
+	if _, err := file.build(); err == nil || !strings.Contains(err.Error(), "the same flag --type") {
+		t.Errorf("build() = %v, want the flag reported", err)
+	}
+}
+
+func TestJSONQuestionOrder(t *testing.T) {
+	content := `{"start": "x", "questions": {"zeta": {"key": "ZETA", "next": "z"}, "alpha": {"key": "ALPHA"}, "mid": {}}}`
+	ids := jsonQuestionOrder([]byte(content))
+	if strings.Join(ids, ",") != "zeta,alpha,mid" {
+		t.Errorf("jsonQuestionOrder() = %v, want the order of the file", ids)
+	}
+}
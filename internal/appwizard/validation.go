@@ -0,0 +1,164 @@
+package appwizard
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type questionType int
+
+const (
+	stringType questionType = iota
+	intType
+	portType
+	urlType
+	hostnameType
+	pathType
+	emailType
+	boolType
+	multiSelectType
+)
+
+var questionTypeNames = map[questionType]string{
+	stringType:      "string",
+	intType:         "int",
+	portType:        "port",
+	urlType:         "URL",
+	hostnameType:    "hostname",
+	pathType:        "path",
+	emailType:       "email",
+	boolType:        "boolean",
+	multiSelectType: "list",
+}
+
+var hostnameRegexp = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?)*$`)
+
+// check validates a value against the type of the question and its custom validator, empty values are checked by mandatory
+func (q question) check(value string) error {
+	if value == "" || len(q.options) > 0 {
+		return nil
+	}
+
+	if err := checkType(q, value); err != nil {
+		return fmt.Errorf("%s %s", q.name(), err)
+	}
+
+	if q.validate != nil {
+		if err := q.validate(value); err != nil {
+			return fmt.Errorf("%s %s", q.name(), err)
+		}
+	}
+
+	return nil
+}
+
+func checkType(q question, value string) error {
+	switch q.kind {
+	case intType:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be a whole number")
+		}
+		if q.min < q.max && (number < q.min || number > q.max) {
+			return fmt.Errorf("must be between %d and %d", q.min, q.max)
+		}
+	case portType:
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("must be a port number between 1 and 65535")
+		}
+	case urlType:
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("must be an http or https URL, like http://localhost:8080")
+		}
+	case hostnameType:
+		if net.ParseIP(value) == nil && !hostnameRegexp.MatchString(value) {
+			return fmt.Errorf("must be a hostname or an IP address")
+		}
+	case pathType:
+		if strings.ContainsRune(value, 0) {
+			return fmt.Errorf("must be a valid path")
+		}
+	case emailType:
+		address, err := mail.ParseAddress(value)
+		if err != nil || address.Address != value {
+			return fmt.Errorf("must be an email address")
+		}
+	case boolType:
+		if _, ok := parseBool(value); !ok {
+			return fmt.Errorf("must be yes or no")
+		}
+	}
+
+	return nil
+}
+
+// typeName describes the type of the question for the flag help
+func (q question) typeName() string {
+	if q.kind == intType && q.min < q.max {
+		return fmt.Sprintf("int %d-%d", q.min, q.max)
+	}
+
+	return questionTypeNames[q.kind]
+}
+
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "true", "1", "on":
+		return true, true
+	case "no", "n", "false", "0", "off":
+		return false, true
+	}
+
+	return false, false
+}
+
+// oneOf returns a validator accepting only the listed values
+func oneOf(values ...string) func(string) error {
+	return func(value string) error {
+		if sliceContains(values, value) {
+			return nil
+		}
+
+		return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+// matches returns a validator accepting only values matching the regular expression
+func matches(re *regexp.Regexp) func(string) error {
+	return func(value string) error {
+		if re.MatchString(value) {
+			return nil
+		}
+
+		return fmt.Errorf("must match %s", re.String())
+	}
+}
+
+// consistencyWarnings returns the problems between answers which are allowed, but probably mistakes
+func consistencyWarnings(responses []EnvData) []string {
+	warnings := make([]string, 0)
+	appURL := valueOf(responses, "APP_URL")
+	listeningPort := valueOf(responses, "HTTP_LISTENING_PORT")
+	if parsed, err := url.Parse(appURL); err == nil && parsed.Port() != "" && listeningPort != "" && parsed.Port() != listeningPort {
+		warnings = append(warnings, fmt.Sprintf("APP_URL port %s does not match HTTP_LISTENING_PORT %s", parsed.Port(), listeningPort))
+	}
+
+	return warnings
+}
+
+func valueOf(responses []EnvData, key string) string {
+	for _, response := range responses {
+		if response.Key == key {
+			return response.Value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,180 @@
+package appwizard
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/olbrichattila/creategofra/internal/specio"
+)
+
+// step is an answered question, the walk can be rewound to any of its steps
+type step struct {
+	question *question
+	answer   *answer
+	root     *question
+	asked    bool
+}
+
+// walk asks the questions of the root question graphs in order, roots returns the roots for the answers given so far.
+// Going back rewinds to the previous asked question, dropping the answers of the branch it abandons.
+// When every question is answered the answers are summarized, any of them can be edited before they are accepted.
+func (w *wizard) walk(roots func(responses []EnvData) []*question) ([]EnvData, error) {
+	steps := make([]step, 0)
+	previous := make(map[*question]string)
+	var edited *question
+
+	for {
+		q, root := nextQuestion(steps, roots)
+		if q == nil {
+			for _, warning := range consistencyWarnings(responsesOf(steps)) {
+				fmt.Fprintln(specio.Output, "Warning: "+warning)
+			}
+
+			// the summary is a menu to edit the answers on a terminal, lines read from a pipe are final
+			if w.options.NonInteractive || !specio.IsTerminal() {
+				w.record(steps)
+				return responsesOf(steps), nil
+			}
+
+			i, err := summary(steps)
+			if errors.Is(err, specio.ErrBack) {
+				steps = rewind(steps)
+				continue
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			if i < 0 {
+				w.record(steps)
+				return responsesOf(steps), nil
+			}
+
+			editedStep := steps[i]
+			if len(editedStep.question.options) > 0 {
+				// a selection may branch differently, the questions after it are asked again
+				steps = steps[:i]
+				edited = editedStep.question
+				continue
+			}
+
+			editedAnswer, err := w.ask(editedStep.question, editedStep.answer.value)
+			if err != nil && !errors.Is(err, specio.ErrBack) {
+				return nil, err
+			}
+
+			if err == nil {
+				steps[i].answer = &answer{value: editedAnswer.value, nextQuestion: editedStep.answer.nextQuestion}
+				previous[editedStep.question] = editedAnswer.value
+			}
+			continue
+		}
+
+		currentValue, ok := previous[q]
+		if !ok {
+			currentValue = lookupValue(w.envContent, q.key)
+			if currentValue == "" {
+				currentValue = q.defaultAnswer
+			}
+		}
+
+		var given *answer
+		var err error
+		asked := w.asks(q) || q == edited
+		if q == edited {
+			edited = nil
+			given, err = w.ask(q, currentValue)
+		} else {
+			given, err = w.answer(q, currentValue)
+		}
+
+		if errors.Is(err, specio.ErrBack) {
+			steps = rewind(steps)
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		previous[q] = given.value
+		steps = append(steps, step{question: q, answer: given, root: root, asked: asked})
+	}
+}
+
+// nextQuestion returns the question following the last step. When its branch ended, it is the next branch of the
+// latest multiselect answer, or the first root not walked yet.
+func nextQuestion(steps []step, roots func(responses []EnvData) []*question) (*question, *question) {
+	if len(steps) > 0 {
+		last := steps[len(steps)-1]
+		if last.answer.nextQuestion != nil {
+			return last.answer.nextQuestion, last.root
+		}
+	}
+
+	walked := make(map[*question]bool)
+	for _, s := range steps {
+		walked[s.root] = true
+		walked[s.question] = true
+	}
+
+	for i := len(steps) - 1; i >= 0; i-- {
+		for _, branch := range steps[i].answer.branches {
+			if !walked[branch] {
+				return branch, branch
+			}
+		}
+	}
+
+	for _, root := range roots(responsesOf(steps)) {
+		if !walked[root] {
+			return root, root
+		}
+	}
+
+	return nil, nil
+}
+
+// rewind drops the steps up to and including the last question asked from the user
+func rewind(steps []step) []step {
+	for i := len(steps) - 1; i >= 0; i-- {
+		if steps[i].asked {
+			return steps[:i]
+		}
+	}
+
+	return steps[:0]
+}
+
+func responsesOf(steps []step) []EnvData {
+	responses := make([]EnvData, 0)
+	for _, s := range steps {
+		if s.question.key != "" && s.answer.value != "" {
+			responses = append(responses, EnvData{Key: s.question.key, Value: s.answer.value})
+		}
+	}
+
+	return responses
+}
+
+// summary lists the answers and returns the index of the step the user wants to edit, or -1 to accept them
+func summary(steps []step) (int, error) {
+	labels := []string{"Accept the answers"}
+	editable := make([]int, 0)
+	for i, s := range steps {
+		if s.question.name() == "" {
+			continue
+		}
+		editable = append(editable, i)
+		labels = append(labels, fmt.Sprintf("%s=%s", s.question.name(), s.question.display(s.answer.value)))
+	}
+
+	selected, err := specio.Select("Summary, select an answer to change it:", labels, 0)
+	fmt.Fprintln(specio.Output, "")
+	if err != nil || selected == 0 {
+		return -1, err
+	}
+
+	return editable[selected-1], nil
+}
@@ -0,0 +1,348 @@
+// Package scaffold collects the files of a new project in memory, so they can be previewed before written to disk
+package scaffold
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// File is a file, a directory or a symbolic link of the generated project, the name is slash separated and relative
+// to the project root
+type File struct {
+	Name string
+	// Data is the content of a file added in memory, files added with AddSource are read when written
+	Data  []byte
+	IsDir bool
+	// Link is the target of a symbolic link, relative to the directory of the link
+	Link string
+	// Mode are the permission bits, DefaultFileMode or DefaultDirMode when not set
+	Mode fs.FileMode
+	// open opens the content of a file added with AddSource, it has size bytes
+	open func() (io.ReadCloser, error)
+	size int64
+	// transform changes the content when it is read or written, see Project.Transform
+	transform func(content []byte) ([]byte, error)
+}
+
+// DefaultFileMode and DefaultDirMode are the permissions of the files and directories without mode
+const (
+	DefaultFileMode fs.FileMode = 0644
+	DefaultDirMode  fs.FileMode = 0755
+)
+
+// perm returns the permission bits the file is written with
+func (f File) perm() fs.FileMode {
+	if f.Mode.Perm() != 0 {
+		return f.Mode.Perm()
+	}
+
+	if f.IsDir {
+		return DefaultDirMode
+	}
+
+	return DefaultFileMode
+}
+
+// Size returns the size of the content of the file, before it is transformed
+func (f File) Size() int64 {
+	if f.open != nil {
+		return f.size
+	}
+
+	return int64(len(f.Data))
+}
+
+// Open opens the content of the file for reading, the caller closes it. A transformed file is read and
+// transformed first.
+func (f File) Open() (io.ReadCloser, error) {
+	if f.transform == nil {
+		return f.openSource()
+	}
+
+	var content bytes.Buffer
+	if err := copySource(&content, f); err != nil {
+		return nil, err
+	}
+
+	transformed, err := f.transform(content.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(transformed)), nil
+}
+
+// openSource opens the content of the file as it was added
+func (f File) openSource() (io.ReadCloser, error) {
+	if f.open != nil {
+		return f.open()
+	}
+
+	return io.NopCloser(bytes.NewReader(f.Data)), nil
+}
+
+// Project contains the files of the generated project in the order they were added
+type Project struct {
+	files []File
+	index map[string]int
+}
+
+// New creates an empty project
+func New() *Project {
+	return &Project{index: make(map[string]int)}
+}
+
+// Add adds a file to the project, replacing its content if it was already added, the mode is kept then
+func (p *Project) Add(name string, data []byte) {
+	var mode fs.FileMode
+	if i, ok := p.index[path.Clean(name)]; ok {
+		mode = p.files[i].Mode
+	}
+
+	p.AddFile(name, data, mode)
+}
+
+// AddFile adds a file with the given permissions to the project
+func (p *Project) AddFile(name string, data []byte, mode fs.FileMode) {
+	p.add(File{Name: path.Clean(name), Data: data, Mode: mode.Perm()})
+}
+
+// AddSource adds a file with the given permissions to the project, its content of size bytes is opened only
+// when it is read or written, so the content of large templates is not kept in memory
+func (p *Project) AddSource(name string, size int64, mode fs.FileMode, open func() (io.ReadCloser, error)) {
+	p.add(File{Name: path.Clean(name), Mode: mode.Perm(), open: open, size: size})
+}
+
+// AddDir adds an empty directory with the given permissions to the project, zero mode is DefaultDirMode
+func (p *Project) AddDir(name string, mode fs.FileMode) {
+	p.add(File{Name: path.Clean(name), IsDir: true, Mode: mode.Perm()})
+}
+
+// Chmod sets the permissions of a file or a directory of the project
+func (p *Project) Chmod(name string, mode fs.FileMode) {
+	if i, ok := p.index[path.Clean(name)]; ok {
+		p.files[i].Mode = mode.Perm()
+	}
+}
+
+// AddLink adds a symbolic link to the project
+func (p *Project) AddLink(name, target string) {
+	p.add(File{Name: path.Clean(name), Link: target})
+}
+
+// Transform sets a transformation of the content of a file, like formatting it. The content is transformed when
+// it is read or written, so the transformed content of only one file at a time is in memory. The transformations
+// are applied in the order they were set, they are dropped when the file is replaced.
+func (p *Project) Transform(name string, transform func(content []byte) ([]byte, error)) {
+	i, ok := p.index[path.Clean(name)]
+	if !ok {
+		return
+	}
+
+	previous := p.files[i].transform
+	if previous == nil {
+		p.files[i].transform = transform
+		return
+	}
+
+	p.files[i].transform = func(content []byte) ([]byte, error) {
+		content, err := previous(content)
+		if err != nil {
+			return nil, err
+		}
+
+		return transform(content)
+	}
+}
+
+// Remove removes a file or a directory from the project, the files in the directory are not removed
+func (p *Project) Remove(name string) {
+	i, ok := p.index[path.Clean(name)]
+	if !ok {
+		return
+	}
+
+	p.files = append(p.files[:i], p.files[i+1:]...)
+	p.index = make(map[string]int, len(p.files))
+	for i, file := range p.files {
+		p.index[file.Name] = i
+	}
+}
+
+// Read returns the content of a file added to the project, fs.ErrNotExist if there is no such file
+func (p *Project) Read(name string) ([]byte, error) {
+	i, ok := p.index[path.Clean(name)]
+	if !ok || p.files[i].IsDir || p.files[i].Link != "" {
+		return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+
+	return readAll(p.files[i])
+}
+
+// Files returns the files and directories of the project
+func (p *Project) Files() []File {
+	return p.files
+}
+
+// Size returns the total size of the content of the files
+func (p *Project) Size() int64 {
+	var size int64
+	for _, file := range p.files {
+		size += file.Size()
+	}
+
+	return size
+}
+
+// Write writes the project to the given directory, progress, if set, is called with each file before it is written.
+// The permissions are reduced by the umask as for any new file, the owner can always write the directories, so
+// their files can be written. Every file is opened, copied and closed before the next one, a failed write or close
+// is returned, so a full disk does not leave truncated files. Nothing is written through a symbolic link.
+func (p *Project) Write(dir string, progress func(file File)) error {
+	for _, file := range p.files {
+		if progress != nil {
+			progress(file)
+		}
+
+		if !filepath.IsLocal(filepath.FromSlash(file.Name)) {
+			return fmt.Errorf("file %s is outside of the project directory", file.Name)
+		}
+
+		if err := checkLinks(dir, file); err != nil {
+			return err
+		}
+
+		targetFileName := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if file.IsDir {
+			if err := os.MkdirAll(targetFileName, file.perm()|0700); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(targetFileName), DefaultDirMode); err != nil {
+			return fmt.Errorf("failed to create directory for file: %w", err)
+		}
+
+		if file.Link != "" {
+			if err := os.Symlink(filepath.FromSlash(file.Link), targetFileName); err != nil {
+				return fmt.Errorf("failed to create symbolic link: %w", err)
+			}
+			continue
+		}
+
+		if err := writeFile(targetFileName, file); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Print lists the files of the project with their size
+func (p *Project) Print(w io.Writer) {
+	for _, file := range p.files {
+		if file.IsDir {
+			fmt.Fprintf(w, "  %s/\n", file.Name)
+			continue
+		}
+		if file.Link != "" {
+			fmt.Fprintf(w, "  %s -> %s\n", file.Name, file.Link)
+			continue
+		}
+		fmt.Fprintf(w, "  %s (%d bytes, %04o)\n", file.Name, file.Size(), file.perm())
+	}
+}
+
+func (p *Project) add(file File) {
+	if i, ok := p.index[file.Name]; ok {
+		p.files[i] = file
+		return
+	}
+
+	p.index[file.Name] = len(p.files)
+	p.files = append(p.files, file)
+}
+
+// checkLinks rejects a file whose directory is, or is in, a symbolic link written before,
+// so a link cannot redirect the files into another directory
+func checkLinks(dir string, file File) error {
+	parent := path.Dir(file.Name)
+	if file.IsDir {
+		parent = file.Name
+	}
+
+	for current := parent; current != "." && current != "/"; current = path.Dir(current) {
+		info, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(current)))
+		if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%s would be written through the symbolic link %s", file.Name, current)
+		}
+	}
+
+	return nil
+}
+
+// readAll reads the content of a file, failing if it is not of the size it was added with, transformed if set
+func readAll(file File) ([]byte, error) {
+	var content bytes.Buffer
+	if err := copyContent(&content, file); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+	}
+
+	return content.Bytes(), nil
+}
+
+func writeFile(name string, file File) error {
+	out, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, file.perm())
+	if err != nil {
+		return err
+	}
+
+	if err := copyContent(out, file); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// copyContent copies the transformed content of the file, the source is closed before it returns
+func copyContent(w io.Writer, file File) error {
+	if file.transform == nil {
+		return copySource(w, file)
+	}
+
+	in, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	_, err = io.Copy(w, in)
+	return err
+}
+
+// copySource copies the content of the file as it was added, failing if it is not of the size it was added with
+func copySource(w io.Writer, file File) error {
+	in, err := file.openSource()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	written, err := io.Copy(w, io.LimitReader(in, file.Size()+1))
+	if err != nil {
+		return err
+	}
+
+	if written != file.Size() {
+		return fmt.Errorf("size changed from %d to %d bytes", file.Size(), written)
+	}
+
+	return nil
+}
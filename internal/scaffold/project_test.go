@@ -0,0 +1,114 @@
+package scaffold
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	p := New()
+	p.AddDir("app", 0)
+	p.AddFile("app/main.go", []byte("package main\n"), 0)
+	p.AddFile("scripts/run.sh", []byte("#!/bin/sh\n"), 0755)
+	p.AddLink("current", "app")
+
+	dir := t.TempDir()
+	written := make([]string, 0)
+	if err := p.Write(dir, func(file File) { written = append(written, file.Name) }); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(written) != 4 {
+		t.Errorf("progress called with %v", written)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "current", "main.go"))
+	if err != nil || string(content) != "package main\n" {
+		t.Errorf("main.go = %q, %v", content, err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "scripts", "run.sh"))
+	if err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("run.sh is not executable: %v, %v", info, err)
+	}
+}
+
+func TestWriteRejectsFilesThroughLinks(t *testing.T) {
+	tests := map[string]func(p *Project){
+		"file":      func(p *Project) { p.Add("migrations/1.sql", []byte("x")) },
+		"directory": func(p *Project) { p.AddDir("migrations/sub", 0) },
+		"link":      func(p *Project) { p.AddLink("migrations/sub", "x") },
+	}
+
+	for name, add := range tests {
+		outside := t.TempDir()
+		dir := t.TempDir()
+		p := New()
+		p.AddLink("migrations", outside)
+		add(p)
+
+		if err := p.Write(dir, nil); err == nil {
+			t.Errorf("%s: Write() wrote through the link", name)
+		}
+
+		entries, _ := os.ReadDir(outside)
+		if len(entries) > 0 {
+			t.Errorf("%s: %d entries written outside of the project", name, len(entries))
+		}
+	}
+}
+
+func TestWriteRejectsOutsideNames(t *testing.T) {
+	for _, name := range []string{"../x", "/x"} {
+		p := New()
+		p.files = append(p.files, File{Name: name, Data: []byte("x")})
+		if err := p.Write(t.TempDir(), nil); err == nil {
+			t.Errorf("Write() wrote %s", name)
+		}
+	}
+}
+
+func TestWriteRejectsWrongSize(t *testing.T) {
+	for _, content := range []string{"short", "longer than declared"} {
+		p := New()
+		p.AddSource("a.txt", 10, 0, func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(content)), nil
+		})
+
+		if err := p.Write(t.TempDir(), nil); err == nil || !strings.Contains(err.Error(), "size changed") {
+			t.Errorf("Write() of %q = %v", content, err)
+		}
+	}
+}
+
+func TestTransformIsAppliedWhenWritten(t *testing.T) {
+	opened := 0
+	p := New()
+	p.AddSource("main.go", 5, 0, func() (io.ReadCloser, error) {
+		opened++
+		return io.NopCloser(strings.NewReader("hello")), nil
+	})
+	p.Transform("main.go", func(content []byte) ([]byte, error) {
+		return []byte(strings.ToUpper(string(content))), nil
+	})
+	p.Transform("main.go", func(content []byte) ([]byte, error) {
+		return append(content, '!'), nil
+	})
+
+	if opened != 0 {
+		t.Errorf("source opened %d times before the project is written", opened)
+	}
+
+	dir := t.TempDir()
+	if err := p.Write(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil || string(content) != "HELLO!" {
+		t.Errorf("main.go = %q, %v", content, err)
+	}
+}
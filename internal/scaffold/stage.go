@@ -0,0 +1,124 @@
+package scaffold
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Stage is a temporary directory next to the target directory, the project is generated into it
+// and moved in place only when every step succeeded
+type Stage struct {
+	// Dir is the project directory in the staging directory
+	Dir      string
+	staging  string
+	target   string
+	mu       sync.Mutex
+	finished bool
+	// parents are the missing parent directories of the target created for the stage, the deepest first
+	parents []string
+}
+
+// Stage writes the project to a new staging directory next to the target directory, progress is passed to Write.
+// The missing parent directories of the target are created, and removed again on rollback.
+func (p *Project) Stage(target string, progress func(file File)) (*Stage, error) {
+	parent := filepath.Dir(filepath.Clean(target))
+	parents := missingDirs(parent)
+	if err := os.MkdirAll(parent, DefaultDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create parent directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(parent, "."+filepath.Base(target)+".creategofra-*")
+	if err != nil {
+		removeDirs(parents)
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	// the temporary directory is only accessible by the owner, the project directory is created in it
+	// like any other directory, so it is moved in place with the permissions the umask allows
+	stage := &Stage{Dir: filepath.Join(staging, filepath.Base(target)), staging: staging, target: target, parents: parents}
+	if err := os.Mkdir(stage.Dir, DefaultDirMode); err != nil {
+		stage.Rollback()
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	if err := p.Write(stage.Dir, progress); err != nil {
+		stage.Rollback()
+		return nil, err
+	}
+
+	return stage, nil
+}
+
+// Commit moves the project directory to the target directory and removes the staging directory
+func (s *Stage) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return fmt.Errorf("staging directory %s is already finished", s.Dir)
+	}
+
+	if _, err := os.Stat(s.target); err == nil {
+		return fmt.Errorf("'%s' already exists", s.target)
+	}
+
+	if err := os.Rename(s.Dir, s.target); err != nil {
+		return fmt.Errorf("failed to move project in place: %w", err)
+	}
+	s.finished = true
+
+	// the staging directory is empty now
+	if err := os.Remove(s.staging); err != nil {
+		return fmt.Errorf("project is in place, but failed to remove the staging directory: %w", err)
+	}
+
+	return nil
+}
+
+// Rollback removes the staging directory and the parent directories created for it, it does nothing once the
+// stage is committed
+func (s *Stage) Rollback() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return nil
+	}
+	s.finished = true
+
+	if err := os.RemoveAll(s.staging); err != nil {
+		return err
+	}
+
+	return removeDirs(s.parents)
+}
+
+// missingDirs returns the directories of the path which do not exist, the deepest first
+func missingDirs(dir string) []string {
+	missing := make([]string, 0)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return missing
+		}
+		missing = append(missing, dir)
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return missing
+		}
+		dir = parent
+	}
+}
+
+// removeDirs removes the empty directories in order
+func removeDirs(dirs []string) error {
+	for _, dir := range dirs {
+		if err := os.Remove(dir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
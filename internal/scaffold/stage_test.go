@@ -0,0 +1,68 @@
+//go:build unix
+
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestStageCommit(t *testing.T) {
+	umask := syscall.Umask(022)
+	defer syscall.Umask(umask)
+
+	for _, name := range []string{"myapp", "sub/dir/myapp"} {
+		testStageCommit(t, filepath.Join(t.TempDir(), filepath.FromSlash(name)))
+	}
+}
+
+func testStageCommit(t *testing.T, target string) {
+	t.Helper()
+
+	p := New()
+	p.Add("main.go", []byte("package main\n"))
+
+	stage, err := p.Stage(target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := stage.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Mode().Perm() != DefaultDirMode {
+		t.Errorf("project directory mode %04o, want %04o", info.Mode().Perm(), DefaultDirMode)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(target))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("staging directory left behind: %v, %v", entries, err)
+	}
+}
+
+func TestStageRollback(t *testing.T) {
+	for _, name := range []string{"myapp", "sub/dir/myapp"} {
+		dir := t.TempDir()
+		stage, err := New().Stage(filepath.Join(dir, filepath.FromSlash(name)), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := stage.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) != 0 {
+			t.Errorf("%s: staging directory left behind: %v, %v", name, entries, err)
+		}
+	}
+}
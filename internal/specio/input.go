@@ -1,11 +1,12 @@
 package specio
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/eiannone/keyboard"
+	"github.com/rivo/uniseg"
 )
 
 const (
@@ -13,37 +14,76 @@ const (
 	showCursor  = "\033[?25h"
 	cursorBegin = "\033[45m"
 	cursorEnd   = "\033[0m"
+	maskChar    = "*"
 )
 
-func Input(prompt, defaultTxt string) string {
-	result := defaultTxt
-	curPos := len(defaultTxt)
-	maxLen := curPos
+// ErrBack is returned when the user asks to go back to the previous question with Ctrl-B or Shift-Tab
+var ErrBack = errors.New("back to previous question")
+
+// ErrAborted is returned when the user aborts with Ctrl-C, or with Esc if EscAborts is set
+var ErrAborted = errors.New("aborted by the user")
+
+// EscAborts makes Esc abort like Ctrl-C, otherwise Esc confirms the input
+var EscAborts = true
+
+// Input reads a line of text, the default text is prefilled and can be edited
+func Input(prompt, defaultTxt string) (string, error) {
+	return readLine(prompt, defaultTxt, false)
+}
+
+// Secret reads a line of text like Input, showing mask characters instead of the text
+func Secret(prompt, defaultTxt string) (string, error) {
+	return readLine(prompt, defaultTxt, true)
+}
+
+func readLine(prompt, defaultTxt string, secret bool) (string, error) {
+	if !IsTerminal() {
+		return readPlainLine(prompt, defaultTxt, secret)
+	}
+
+	result := newLine(defaultTxt)
+	curPos := len(result)
+	maxWidth := result.width()
 
 	if err := keyboard.Open(); err != nil {
-		log.Fatal(err)
+		return "", err
 	}
 	defer keyboard.Close()
 
 	for {
-		displayText(prompt, result, curPos, maxLen)
+		shown := result
+		if secret {
+			shown = result.masked()
+		}
+		if shown.width() > maxWidth {
+			maxWidth = shown.width()
+		}
+		displayText(prompt, shown, curPos, maxWidth)
+
 		char, key, err := keyboard.GetKey()
 		if err != nil {
-			fmt.Println(err.Error())
-			break
+			fmt.Fprint(Output, showCursor)
+			return "", err
 		}
 
-		if key == 3 {
-			fmt.Print(showCursor)
-			break
+		if key == keyboard.KeyCtrlC || (key == keyboard.KeyEsc && char == 0 && EscAborts) {
+			fmt.Fprint(Output, showCursor)
+			return "", ErrAborted
+		}
+
+		// Shift-Tab is not known by the keyboard package, it is reported as Esc followed by '['
+		if key == keyboard.KeyCtrlB || (key == keyboard.KeyEsc && char == '[') {
+			fmt.Fprint(Output, showCursor)
+			return "", ErrBack
+		}
+
+		if key == keyboard.KeySpace {
+			char = ' '
+			key = 0
 		}
 
 		if key == 0 {
-			result = recordKeyPress(result, curPos, char)
-			curPos++
-			if curPos > maxLen {
-				maxLen = curPos
-			}
+			result, curPos = recordKeyPress(result, curPos, char)
 			continue
 		}
 
@@ -54,9 +94,6 @@ func Input(prompt, defaultTxt string) string {
 
 		if key == keyboard.KeyArrowRight && curPos < len(result) {
 			curPos++
-			if curPos > maxLen {
-				maxLen = curPos
-			}
 			continue
 		}
 
@@ -82,66 +119,73 @@ func Input(prompt, defaultTxt string) string {
 		}
 
 		if key == keyboard.KeyEsc || key == keyboard.KeyEnter || key == keyboard.KeyTab {
-			fmt.Print(showCursor)
+			fmt.Fprint(Output, showCursor)
 			break
 		}
 	}
 
-	return result
+	return result.String(), nil
 }
 
-func displayText(prompt, s string, p, m int) {
-	fmt.Print("\r" + prompt + strings.Repeat(" ", m+2))
-	result := hideCursor + "\r" + prompt
-	if len(s) == p {
-		fmt.Print(result + s + cursorBegin + " " + cursorEnd)
-		return
-	}
-	for i, c := range s {
-		if i == p {
-			result += cursorBegin + string(c) + cursorEnd
-			continue
-		}
-		result += string(c)
+// line is the edited text split to grapheme clusters, the cursor moves over a whole character
+// even if it is made of several runes, like an accented letter or an emoji
+type line []string
+
+func newLine(s string) line {
+	result := make(line, 0, len(s))
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		result = append(result, graphemes.Str())
 	}
 
-	fmt.Print(result)
+	return result
 }
 
-func recordKeyPress(s string, p int, c rune) string {
-	if len(s) == p {
-		return s + string(c)
-	}
+func (l line) String() string {
+	return strings.Join(l, "")
+}
 
-	result := ""
-	for i, l := range s {
+// width is the number of terminal cells the text takes, wide characters take two
+func (l line) width() int {
+	return uniseg.StringWidth(l.String())
+}
+
+func (l line) masked() line {
+	return newLine(strings.Repeat(maskChar, len(l)))
+}
+
+// displayText redraws the prompt and the text with the cursor at p, clearing the cells of the longest text m
+func displayText(prompt string, l line, p, m int) {
+	fmt.Fprint(Output, "\r"+prompt+strings.Repeat(" ", m+2))
+	result := hideCursor + "\r" + prompt
+	for i, c := range l {
 		if i == p {
-			result += string(c)
+			result += cursorBegin + c + cursorEnd
+			continue
 		}
-		result += string(l)
+		result += c
 	}
 
-	return result
+	if len(l) == p {
+		result += cursorBegin + " " + cursorEnd
+	}
+
+	fmt.Fprint(Output, result)
 }
 
-func removeCharAtCursor(s string, p int) string {
-	result := ""
-	for i, l := range s {
-		if i != p-1 {
-			result += string(l)
-		}
-	}
+// recordKeyPress inserts the character at the cursor and returns the new cursor position,
+// a combining character joins the character before it
+func recordKeyPress(l line, p int, c rune) (line, int) {
+	before := newLine(l[:p].String() + string(c))
+	after := newLine(before.String() + l[p:].String())
 
-	return result
+	return after, len(before)
 }
 
-func removeCharAfterCursor(s string, p int) string {
-	result := ""
-	for i, l := range s {
-		if i != p {
-			result += string(l)
-		}
-	}
+func removeCharAtCursor(l line, p int) line {
+	return append(l[:p-1:p-1], l[p:]...)
+}
 
-	return result
+func removeCharAfterCursor(l line, p int) line {
+	return append(l[:p:p], l[p+1:]...)
 }
@@ -0,0 +1,47 @@
+package specio
+
+import "testing"
+
+func TestRecordKeyPress(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		p      int
+		c      rune
+		want   string
+		length int
+		cursor int
+		width  int
+	}{
+		{"accented letter", "caf", 3, 'é', "café", 4, 4, 4},
+		{"combining mark after a letter", "cafe", 4, '\u0301', "cafe\u0301", 4, 4, 4},
+		{"wide character", "日", 1, '本', "日本", 2, 2, 4},
+		{"skin tone", "👍", 1, '\U0001F3FD', "👍🏽", 1, 1, 2},
+		{"insert in the middle", "ac", 1, 'b', "abc", 3, 2, 3},
+		{"insert before a wide character", "a本", 1, '日', "a日本", 3, 2, 5},
+	}
+
+	for _, test := range tests {
+		got, p := recordKeyPress(newLine(test.text), test.p, test.c)
+		if got.String() != test.want || len(got) != test.length || p != test.cursor || got.width() != test.width {
+			t.Errorf("%s: recordKeyPress() = %q with %d clusters, width %d and cursor %d, want %q, %d, %d and %d",
+				test.name, got, len(got), got.width(), p, test.want, test.length, test.width, test.cursor)
+		}
+	}
+}
+
+func TestRemoveChar(t *testing.T) {
+	text := newLine("a日👍🏽b")
+
+	if got := removeCharAtCursor(text, 3); got.String() != "a日b" {
+		t.Errorf("removeCharAtCursor() = %q, want the emoji removed", got)
+	}
+
+	if got := removeCharAfterCursor(text, 1); got.String() != "a👍🏽b" {
+		t.Errorf("removeCharAfterCursor() = %q, want the wide character removed", got)
+	}
+
+	if text.String() != "a日👍🏽b" {
+		t.Errorf("removing a character changed the text to %q", text)
+	}
+}
@@ -0,0 +1,155 @@
+package specio
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Output is where the prompts are written, so stdout stays clean for machine-readable output
+var Output io.Writer = os.Stderr
+
+// ErrNoInput is returned when stdin is not a terminal and it has no more lines to answer with
+var ErrNoInput = errors.New("no more input on stdin")
+
+var lines *bufio.Reader
+
+// IsTerminal tells if stdin is a terminal, otherwise the answers are read line by line
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// readPlainLine reads an answer from a line of stdin, an empty line keeps the default text
+func readPlainLine(prompt, defaultTxt string, secret bool) (string, error) {
+	shown := defaultTxt
+	if secret {
+		shown = newLine(defaultTxt).masked().String()
+	}
+
+	if shown == "" {
+		fmt.Fprint(Output, prompt)
+	} else {
+		fmt.Fprintf(Output, "%s[%s] ", prompt, shown)
+	}
+
+	response, err := readStdinLine()
+	if err != nil {
+		return "", err
+	}
+
+	if response == "" {
+		return defaultTxt, nil
+	}
+
+	return response, nil
+}
+
+// selectPlain lists the numbered options and reads the number or the text of the selected one from a line of stdin
+func selectPlain(prompt string, options []string, selected int) (int, error) {
+	fmt.Fprintln(Output, prompt)
+	for i, option := range options {
+		fmt.Fprintf(Output, "  %d. %s\n", i+1, option)
+	}
+
+	for {
+		fmt.Fprintf(Output, "Select [%d] ", selected+1)
+		response, err := readStdinLine()
+		if err != nil {
+			return -1, err
+		}
+
+		if response == "" {
+			return selected, nil
+		}
+
+		if i := optionNumber(options, response); i >= 0 {
+			return i, nil
+		}
+
+		fmt.Fprintf(Output, "\nPlease select an option from 1 to %d\n", len(options))
+	}
+}
+
+// multiSelectPlain lists the numbered options and reads the numbers or the texts of the checked ones,
+// separated by commas, from a line of stdin. An empty line keeps the checked options, "none" unchecks all.
+func multiSelectPlain(prompt string, options []string, checked []bool) ([]bool, error) {
+	fmt.Fprintln(Output, prompt)
+	defaults := make([]string, 0)
+	for i, option := range options {
+		fmt.Fprintf(Output, "  %d. %s\n", i+1, option)
+		if checked[i] {
+			defaults = append(defaults, strconv.Itoa(i+1))
+		}
+	}
+
+	for {
+		fmt.Fprintf(Output, "Select, separated by commas [%s] ", strings.Join(defaults, ","))
+		response, err := readStdinLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if response == "" {
+			return checked, nil
+		}
+
+		result := make([]bool, len(options))
+		if strings.EqualFold(response, "none") {
+			return result, nil
+		}
+
+		valid := true
+		for _, item := range strings.Split(response, ",") {
+			i := optionNumber(options, strings.TrimSpace(item))
+			if i < 0 {
+				valid = false
+				break
+			}
+			result[i] = true
+		}
+
+		if valid {
+			return result, nil
+		}
+
+		fmt.Fprintf(Output, "\nPlease select options from 1 to %d, or none\n", len(options))
+	}
+}
+
+// optionNumber returns the index of the option given by its number starting from 1 or by its text, or -1
+func optionNumber(options []string, response string) int {
+	if number, err := strconv.Atoi(response); err == nil && number > 0 && number <= len(options) {
+		return number - 1
+	}
+
+	for i, option := range options {
+		if strings.EqualFold(option, response) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func readStdinLine() (string, error) {
+	if lines == nil {
+		lines = bufio.NewReader(os.Stdin)
+	}
+
+	response, err := lines.ReadString('\n')
+	if err == io.EOF && response == "" {
+		return "", ErrNoInput
+	}
+
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return strings.TrimRight(response, "\r\n"), nil
+}
@@ -0,0 +1,82 @@
+package specio
+
+import (
+	"fmt"
+
+	"github.com/eiannone/keyboard"
+)
+
+// MultiSelect shows the options as a list of checkboxes, the current option is moved with the arrow keys,
+// Space toggles it and Enter confirms. It returns which options are checked.
+func MultiSelect(prompt string, options []string, checked []bool) ([]bool, error) {
+	if len(options) == 0 {
+		return nil, fmt.Errorf("no options to select from")
+	}
+
+	result := make([]bool, len(options))
+	copy(result, checked)
+
+	if !IsTerminal() {
+		return multiSelectPlain(prompt, options, result)
+	}
+
+	if err := keyboard.Open(); err != nil {
+		return nil, err
+	}
+	defer keyboard.Close()
+
+	fmt.Fprint(Output, hideCursor)
+	defer fmt.Fprint(Output, showCursor)
+
+	fmt.Fprintln(Output, prompt+" (Space to toggle, Enter to confirm)")
+	current := 0
+	displayCheckboxes(options, result, current)
+	for {
+		char, key, err := keyboard.GetKey()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case key == keyboard.KeyCtrlB || (key == keyboard.KeyEsc && char == '['):
+			return nil, ErrBack
+		case key == keyboard.KeyCtrlC || (key == keyboard.KeyEsc && char == 0 && EscAborts):
+			return nil, ErrAborted
+		case key == keyboard.KeyEnter:
+			return result, nil
+		case key == keyboard.KeySpace:
+			result[current] = !result[current]
+		case key == keyboard.KeyArrowUp && current > 0:
+			current--
+		case key == keyboard.KeyArrowDown && current < len(options)-1:
+			current++
+		case key == keyboard.KeyHome:
+			current = 0
+		case key == keyboard.KeyEnd:
+			current = len(options) - 1
+		case char >= '1' && char <= '9' && int(char-'1') < len(options):
+			current = int(char - '1')
+			result[current] = !result[current]
+		default:
+			continue
+		}
+
+		fmt.Fprintf(Output, cursorUp, len(options))
+		displayCheckboxes(options, result, current)
+	}
+}
+
+func displayCheckboxes(options []string, checked []bool, current int) {
+	for i, option := range options {
+		box := "[ ] "
+		if checked[i] {
+			box = "[x] "
+		}
+
+		if i == current {
+			fmt.Fprint(Output, clearLine+"> "+box+cursorBegin+option+cursorEnd+"\n")
+			continue
+		}
+		fmt.Fprint(Output, clearLine+"  "+box+option+"\n")
+	}
+}
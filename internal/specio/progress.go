@@ -0,0 +1,98 @@
+package specio
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/term"
+)
+
+// Quiet turns off the progress of the long running tasks
+var Quiet bool
+
+// Progress shows how many of the files and bytes of a task are done and the current file. On a terminal it is
+// one line rewritten in place, otherwise a log line is written per file.
+type Progress struct {
+	task       string
+	files      int
+	size       int64
+	done       int
+	doneSize   int64
+	terminal   bool
+	lineLength int
+}
+
+// NewProgress starts the progress of a task processing the given number of files and bytes
+func NewProgress(task string, files int, size int64) *Progress {
+	p := &Progress{task: task, files: files, size: size, lineLength: 80}
+	if file, ok := Output.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		p.terminal = true
+		if width, _, err := term.GetSize(int(file.Fd())); err == nil && width > 0 {
+			p.lineLength = width - 1
+		}
+	}
+
+	return p
+}
+
+// Step shows the file which is processed next, size is the number of its bytes
+func (p *Progress) Step(name string, size int64) {
+	p.done++
+	p.doneSize += size
+	if Quiet {
+		return
+	}
+
+	if !p.terminal {
+		fmt.Fprintf(Output, "%s: %s (%d/%d)\n", p.task, name, p.done, p.files)
+		return
+	}
+
+	fmt.Fprint(Output, clearLine+truncate(p.status()+"  "+name, p.lineLength))
+}
+
+// Done ends the progress with the number of files and bytes processed
+func (p *Progress) Done() {
+	if Quiet {
+		return
+	}
+
+	if p.terminal {
+		fmt.Fprint(Output, clearLine)
+	}
+	fmt.Fprintln(Output, p.status())
+}
+
+func (p *Progress) status() string {
+	return fmt.Sprintf("%s: %d/%d files, %s/%s", p.task, p.done, p.files, formatSize(p.doneSize), formatSize(p.size))
+}
+
+// truncate cuts the text to the width, so the line is not wrapped and can be rewritten
+func truncate(text string, width int) string {
+	if uniseg.StringWidth(text) <= width {
+		return text
+	}
+
+	result := ""
+	graphemes := uniseg.NewGraphemes(text)
+	for graphemes.Next() {
+		if uniseg.StringWidth(result+graphemes.Str()) > width {
+			break
+		}
+		result += graphemes.Str()
+	}
+
+	return result
+}
+
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,79 @@
+package specio
+
+import (
+	"fmt"
+
+	"github.com/eiannone/keyboard"
+)
+
+const (
+	clearLine = "\r\033[K"
+	cursorUp  = "\033[%dA"
+)
+
+// Select shows the options as a menu, the current option is highlighted and moved with the arrow keys,
+// Enter confirms it. It returns the index of the selected option, or ErrAborted on Ctrl-C.
+func Select(prompt string, options []string, selected int) (int, error) {
+	if len(options) == 0 {
+		return -1, fmt.Errorf("no options to select from")
+	}
+
+	if selected < 0 || selected >= len(options) {
+		selected = 0
+	}
+
+	if !IsTerminal() {
+		return selectPlain(prompt, options, selected)
+	}
+
+	if err := keyboard.Open(); err != nil {
+		return -1, err
+	}
+	defer keyboard.Close()
+
+	fmt.Fprint(Output, hideCursor)
+	defer fmt.Fprint(Output, showCursor)
+
+	fmt.Fprintln(Output, prompt)
+	displayOptions(options, selected)
+	for {
+		char, key, err := keyboard.GetKey()
+		if err != nil {
+			return -1, err
+		}
+
+		switch {
+		case key == keyboard.KeyCtrlB || (key == keyboard.KeyEsc && char == '['):
+			return -1, ErrBack
+		case key == keyboard.KeyCtrlC || (key == keyboard.KeyEsc && char == 0 && EscAborts):
+			return -1, ErrAborted
+		case key == keyboard.KeyEnter:
+			return selected, nil
+		case key == keyboard.KeyArrowUp && selected > 0:
+			selected--
+		case key == keyboard.KeyArrowDown && selected < len(options)-1:
+			selected++
+		case key == keyboard.KeyHome:
+			selected = 0
+		case key == keyboard.KeyEnd:
+			selected = len(options) - 1
+		case char >= '1' && char <= '9' && int(char-'1') < len(options):
+			selected = int(char - '1')
+		default:
+			continue
+		}
+
+		fmt.Fprintf(Output, cursorUp, len(options))
+		displayOptions(options, selected)
+	}
+}
+
+func displayOptions(options []string, selected int) {
+	for i, option := range options {
+		if i == selected {
+			fmt.Fprint(Output, clearLine+"> "+cursorBegin+option+cursorEnd+"\n")
+			continue
+		}
+		fmt.Fprint(Output, clearLine+"  "+option+"\n")
+	}
+}
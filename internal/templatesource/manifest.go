@@ -0,0 +1,181 @@
+package templatesource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest in the root directory of a template, it is not copied to the project
+const ManifestFile = "gofra-template.yaml"
+
+// Manifest describes a template, see files/gofra-template.yaml in the embedded templates
+type Manifest struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Version is the version of the template, it is recorded in the generated project
+	Version string `yaml:"version"`
+	// Module is the module path the Go files of the template import their own packages with
+	Module string `yaml:"module"`
+	// Features are provided by the template, file groups can depend on them
+	Features []string `yaml:"features"`
+	// Questions extend or override the questions of the wizard, in the format of a questions file
+	Questions yaml.Node `yaml:"questions"`
+	// Files are the file groups generated only on a condition
+	Files []FileGroup `yaml:"files"`
+	// Modes set the permissions of files, instead of the permissions in the template
+	Modes []FileMode `yaml:"modes"`
+	// Render are the patterns of the files rendered with text/template
+	Render []string `yaml:"render"`
+	// Delimiters are the left and right delimiters of the rendered files and commands, the files often have
+	// {{ }} of their own, like the views, so [[ ]] is the default
+	Delimiters []string `yaml:"delimiters"`
+	// Post are the commands run in the project directory after the files are written, the arguments are rendered
+	Post [][]string `yaml:"post"`
+}
+
+// FileGroup is a group of files, they are only part of the project when the condition is met
+type FileGroup struct {
+	Name string `yaml:"name"`
+	// When is the name of a feature, or an answer like DB_CONNECTION=pgsql or DB_CONNECTION!=sqlite
+	When string `yaml:"when"`
+	// Paths are slash separated patterns, relative to the project directory, as in path.Match
+	Paths []string `yaml:"paths"`
+}
+
+// FileMode sets the permissions of the files matching the patterns
+type FileMode struct {
+	// Mode are octal permission bits, like 0755 for scripts or 0600 for secrets
+	Mode  string   `yaml:"mode"`
+	Paths []string `yaml:"paths"`
+	perm  fs.FileMode
+}
+
+// Perm returns the permission bits of the mode
+func (m FileMode) Perm() fs.FileMode {
+	return m.perm
+}
+
+// Template is a template opened from one of the sources
+type Template struct {
+	Files    fs.FS
+	Manifest Manifest
+	// Location is where the template was opened from, empty for the embedded templates
+	Location string
+}
+
+// defaultModule is the module path of the Go files of the templates without module in their manifest
+const defaultModule = "gofraapp"
+
+var defaultDelimiters = []string{"[[", "]]"}
+
+// defaultPost are the commands of templates without post steps in their manifest
+var defaultPost = [][]string{
+	{"go", "mod", "init", "[[ .Module ]]"},
+	{"go", "mod", "tidy"},
+}
+
+// maxManifestSize limits the size of the manifest, it is read before the template files are checked
+const maxManifestSize = 1 << 20
+
+// readManifest reads the manifest of the template files, it has to be a regular file, not a link to another file
+func readManifest(files fs.FS) ([]byte, error) {
+	info, err := lstat(files, ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%w: %s is not a regular file", ErrUnsafe, ManifestFile)
+	}
+
+	return ReadFile(files, ManifestFile, maxManifestSize)
+}
+
+// newTemplate reads the manifest of the template files, a template without manifest is named after its location
+func newTemplate(files fs.FS, name string) (*Template, error) {
+	manifest := Manifest{Name: name}
+	content, err := readManifest(files)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	}
+
+	if err == nil {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ManifestFile, err)
+		}
+	}
+
+	if manifest.Module == "" {
+		manifest.Module = defaultModule
+	}
+
+	if manifest.Delimiters == nil {
+		manifest.Delimiters = defaultDelimiters
+	}
+
+	if len(manifest.Delimiters) != 2 || manifest.Delimiters[0] == "" || manifest.Delimiters[1] == "" {
+		return nil, fmt.Errorf("invalid %s: delimiters must be a left and a right delimiter", ManifestFile)
+	}
+
+	if manifest.Post == nil {
+		manifest.Post = defaultPost
+	}
+
+	for _, group := range manifest.Files {
+		for _, pattern := range group.Paths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid %s: file group '%s': invalid pattern '%s'", ManifestFile, group.Name, pattern)
+			}
+		}
+	}
+
+	for i, mode := range manifest.Modes {
+		perm, err := strconv.ParseUint(mode.Mode, 8, 32)
+		if err != nil || perm > 0777 {
+			return nil, fmt.Errorf("invalid %s: invalid mode '%s'", ManifestFile, mode.Mode)
+		}
+		manifest.Modes[i].perm = fs.FileMode(perm)
+
+		for _, pattern := range mode.Paths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid %s: mode %s: invalid pattern '%s'", ManifestFile, mode.Mode, pattern)
+			}
+		}
+	}
+
+	for _, pattern := range manifest.Render {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s: invalid render pattern '%s'", ManifestFile, pattern)
+		}
+	}
+
+	return &Template{Files: files, Manifest: manifest}, nil
+}
+
+// QuestionsFile returns the questions of the manifest in the YAML format of a questions file, or nil if it has none
+func (m Manifest) QuestionsFile() ([]byte, error) {
+	if m.Questions.IsZero() {
+		return nil, nil
+	}
+
+	return yaml.Marshal(&m.Questions)
+}
+
+// Matches tells if a project file name matches any of the patterns
+func Matches(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
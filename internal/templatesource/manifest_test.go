@@ -0,0 +1,43 @@
+package templatesource
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestManifestMustBeRegularFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Symlink(os.DevNull, filepath.Join(dir, ManifestFile)); err != nil {
+		t.Skip(err)
+	}
+
+	if _, err := Open(dir); !errors.Is(err, ErrUnsafe) {
+		t.Errorf("Open() of a linked manifest = %v, want ErrUnsafe", err)
+	}
+
+	data := newZip(t, zipEntry{name: ManifestFile, content: "/dev/zero", link: true})
+	if _, err := Embedded(data); !errors.Is(err, ErrUnsafe) {
+		t.Errorf("Embedded() of a linked manifest = %v, want ErrUnsafe", err)
+	}
+}
+
+func TestManifestLimit(t *testing.T) {
+	data := newZip(t, zipEntry{name: ManifestFile, content: "# " + strings.Repeat("x", maxManifestSize)})
+	if _, err := Embedded(data); !errors.Is(err, ErrUnsafe) {
+		t.Errorf("Embedded() of a large manifest = %v, want ErrUnsafe", err)
+	}
+}
+
+func TestManifestIsOptional(t *testing.T) {
+	template, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if template.Manifest.Module != defaultModule {
+		t.Errorf("Module = %q, want %q", template.Manifest.Module, defaultModule)
+	}
+}
@@ -0,0 +1,178 @@
+package templatesource
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MaxFiles and MaxSize limit the number of files and the total uncompressed size of a template,
+// so an archive cannot fill the disk
+const (
+	MaxFiles = 10000
+	MaxSize  = 256 << 20
+)
+
+// ErrUnsafe is returned for templates which would write outside of the project directory or exceed the limits
+var ErrUnsafe = errors.New("unsafe template")
+
+// CheckName rejects the names of archive entries which would be written outside of the project directory
+func CheckName(name string) error {
+	clean := strings.TrimSuffix(name, "/")
+	if clean == "" || strings.ContainsAny(clean, "\\\x00") || isVolume(clean) || path.IsAbs(clean) || !filepath.IsLocal(clean) {
+		return fmt.Errorf("%w: unsafe path '%s'", ErrUnsafe, name)
+	}
+
+	return nil
+}
+
+// CheckLink rejects symbolic links pointing outside of the project directory, the target is relative to the link
+func CheckLink(name, target string) error {
+	if target == "" || strings.ContainsAny(target, "\\\x00") || isVolume(target) || path.IsAbs(target) ||
+		!filepath.IsLocal(path.Join(path.Dir(name), target)) {
+		return fmt.Errorf("%w: symbolic link %s -> %s points outside of the project", ErrUnsafe, name, target)
+	}
+
+	return nil
+}
+
+// maxLinkHops limits the links followed to resolve a link, like the operating systems do
+const maxLinkHops = 40
+
+// CheckLinks rejects symbolic links pointing outside of the project directory through the other links,
+// the links are targets by slash separated name relative to the project directory. Every link is checked
+// with CheckLink before.
+func CheckLinks(links map[string]string) error {
+	for name := range links {
+		if err := resolveLink(links, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveLink follows the link and the links its target goes through, failing if the path leaves the project
+func resolveLink(links map[string]string, name string) error {
+	resolved := make([]string, 0)
+	pending := strings.Split(path.Dir(name)+"/"+links[name], "/")
+	hops := 0
+	for len(pending) > 0 {
+		element := pending[0]
+		pending = pending[1:]
+
+		switch element {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return fmt.Errorf("%w: symbolic link %s -> %s points outside of the project", ErrUnsafe, name, links[name])
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+
+		resolved = append(resolved, element)
+		target, ok := links[strings.Join(resolved, "/")]
+		if !ok {
+			continue
+		}
+
+		hops++
+		if hops > maxLinkHops {
+			return fmt.Errorf("%w: symbolic link %s -> %s has too many levels of links", ErrUnsafe, name, links[name])
+		}
+		resolved = resolved[:len(resolved)-1]
+		pending = append(strings.Split(target, "/"), pending...)
+	}
+
+	return nil
+}
+
+// isVolume tells if the name starts with a Windows drive letter, like C:
+func isVolume(name string) bool {
+	return len(name) >= 2 && name[1] == ':'
+}
+
+// ReadFile reads a regular file of the template, failing if it is larger than the limit
+func ReadFile(files fs.FS, name string, limit int64) ([]byte, error) {
+	file, err := files.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(content)) > limit {
+		return nil, fmt.Errorf("%w: %s is larger than %d bytes", ErrUnsafe, name, limit)
+	}
+
+	return content, nil
+}
+
+// linkReader is a file system which reads its symbolic links, like dirFS
+type linkReader interface {
+	ReadLink(name string) (string, error)
+}
+
+// ReadLink returns the target of a symbolic link of the template
+func ReadLink(files fs.FS, name string) (string, error) {
+	if linkFiles, ok := files.(linkReader); ok {
+		return linkFiles.ReadLink(name)
+	}
+
+	// zip archives store the target as the content of the link
+	target, err := ReadFile(files, name, 4096)
+	return string(target), err
+}
+
+// lstater is a file system which stats its symbolic links instead of following them, like dirFS
+type lstater interface {
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// lstat returns the file info of a file of the template without following symbolic links
+func lstat(files fs.FS, name string) (fs.FileInfo, error) {
+	if linkFiles, ok := files.(lstater); ok {
+		return linkFiles.Lstat(name)
+	}
+
+	// the symbolic links of zip archives are entries of their own
+	return fs.Stat(files, name)
+}
+
+// dirFS is a template directory, its symbolic links are read instead of followed
+type dirFS struct {
+	fs.FS
+	root string
+}
+
+func newDirFS(root string) dirFS {
+	return dirFS{FS: os.DirFS(root), root: root}
+}
+
+func (d dirFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	target, err := os.Readlink(filepath.Join(d.root, filepath.FromSlash(name)))
+	return filepath.ToSlash(target), err
+}
+
+func (d dirFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.Lstat(filepath.Join(d.root, filepath.FromSlash(name)))
+}
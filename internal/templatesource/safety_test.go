@@ -0,0 +1,173 @@
+package templatesource
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"testing"
+)
+
+// zipEntry is an entry of a test archive, size overrides the declared uncompressed size when set
+type zipEntry struct {
+	name    string
+	content string
+	link    bool
+	size    uint64
+}
+
+func newZip(t *testing.T, entries ...zipEntry) []byte {
+	t.Helper()
+
+	var data bytes.Buffer
+	writer := zip.NewWriter(&data)
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:               entry.name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE([]byte(entry.content)),
+			CompressedSize64:   uint64(len(entry.content)),
+			UncompressedSize64: uint64(len(entry.content)),
+		}
+		if entry.size != 0 {
+			header.UncompressedSize64 = entry.size
+		}
+		header.SetMode(0644)
+		if entry.link {
+			header.SetMode(0777 | 1<<27)
+		}
+
+		w, err := writer.CreateRaw(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return data.Bytes()
+}
+
+func TestCheckName(t *testing.T) {
+	for _, name := range []string{"../x", "a/../../x", "/etc/passwd", "C:/x", "C:x", "a\\..\\..\\x", "a\x00b", ""} {
+		if err := CheckName(name); !errors.Is(err, ErrUnsafe) {
+			t.Errorf("CheckName(%q) = %v, want ErrUnsafe", name, err)
+		}
+	}
+
+	for _, name := range []string{"main.go", "app/views/", "a/../b", "..x"} {
+		if err := CheckName(name); err != nil {
+			t.Errorf("CheckName(%q) = %v, want nil", name, err)
+		}
+	}
+}
+
+func TestCheckLink(t *testing.T) {
+	tests := []struct {
+		name, target string
+		unsafe       bool
+	}{
+		{"a/b", "../../x", true},
+		{"a", "/etc", true},
+		{"a", "C:/x", true},
+		{"a", "..\\x", true},
+		{"a", "", true},
+		{"a/b", "../x", false},
+		{"a", ".", false},
+	}
+
+	for _, test := range tests {
+		err := CheckLink(test.name, test.target)
+		if test.unsafe != errors.Is(err, ErrUnsafe) {
+			t.Errorf("CheckLink(%q, %q) = %v, unsafe %v", test.name, test.target, err, test.unsafe)
+		}
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	tests := []struct {
+		links  map[string]string
+		unsafe bool
+	}{
+		{map[string]string{"a": ".", "b": "a/.."}, true},
+		{map[string]string{"a": ".", "b": "a/..", "c": "b/..", "migrations": "c/PWNED_DIR"}, true},
+		{map[string]string{"a": "sub/x", "sub/x": "../.."}, true},
+		{map[string]string{"a": "b", "b": "a"}, true},
+		{map[string]string{"a": "sub", "b": "a/../x", "sub/y": "../x"}, false},
+		{map[string]string{"views/a": "../shared/a"}, false},
+	}
+
+	for _, test := range tests {
+		err := CheckLinks(test.links)
+		if test.unsafe != errors.Is(err, ErrUnsafe) {
+			t.Errorf("CheckLinks(%v) = %v, unsafe %v", test.links, err, test.unsafe)
+		}
+	}
+}
+
+func TestZipRejectsUnsafeArchives(t *testing.T) {
+	tooMany := make([]zipEntry, 0, MaxFiles+1)
+	for i := 0; i <= MaxFiles; i++ {
+		tooMany = append(tooMany, zipEntry{name: fmt.Sprintf("f%d", i)})
+	}
+
+	tests := map[string][]zipEntry{
+		"zip slip":      {{name: "../evil.sh", content: "x"}},
+		"absolute":      {{name: "/tmp/evil.sh", content: "x"}},
+		"volume":        {{name: "C:/evil.sh", content: "x"}},
+		"backslash":     {{name: "..\\evil.sh", content: "x"}},
+		"too many":      tooMany,
+		"declared size": {{name: "a", content: "x", size: MaxSize}, {name: "b", content: "x", size: 1}},
+	}
+
+	for name, entries := range tests {
+		if _, err := Zip(newZip(t, entries...)); !errors.Is(err, ErrUnsafe) {
+			t.Errorf("%s: Zip() = %v, want ErrUnsafe", name, err)
+		}
+	}
+}
+
+func TestReadFileRejectsWrongDeclaredSize(t *testing.T) {
+	files, err := Zip(newZip(t, zipEntry{name: "bomb", content: string(make([]byte, 1000)), size: 10}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadFile(files, "bomb", MaxSize); err == nil {
+		t.Error("ReadFile() read more bytes than declared")
+	}
+}
+
+func TestReadFileLimit(t *testing.T) {
+	files, err := Zip(newZip(t, zipEntry{name: "big", content: "0123456789"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ReadFile(files, "big", 5)
+	if !errors.Is(err, ErrUnsafe) {
+		t.Fatalf("ReadFile() = %v, want ErrUnsafe", err)
+	}
+
+	if want := "larger than 5 bytes"; !bytes.Contains([]byte(err.Error()), []byte(want)) {
+		t.Errorf("ReadFile() = %v, want %q", err, want)
+	}
+}
+
+func TestReadLinkOfZip(t *testing.T) {
+	files, err := Zip(newZip(t, zipEntry{name: "link", content: "../x", link: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := ReadLink(files, "link")
+	if err != nil || target != "../x" {
+		t.Errorf("ReadLink() = %q, %v", target, err)
+	}
+}
@@ -0,0 +1,150 @@
+// Package templatesource reads project templates from zip archives, local directories and git repositories
+package templatesource
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Open opens a template from a local directory, a local .zip file or a local git repository followed by @ref,
+// like ./starter@v1.2.0. Nothing is downloaded.
+func Open(location string) (*Template, error) {
+	files, name, err := open(location)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := newTemplate(files, name)
+	if err != nil {
+		return nil, err
+	}
+	template.Location = location
+
+	return template, nil
+}
+
+// Embedded opens a template from the data of an embedded zip archive
+func Embedded(data []byte) (*Template, error) {
+	files, err := Zip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTemplate(files, "")
+}
+
+func open(location string) (fs.FS, string, error) {
+	path, ref := splitRef(location)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("template '%s' not found: %w", location, err)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if ref != "" {
+		files, err := gitArchive(path, ref)
+		return files, name, err
+	}
+
+	if info.IsDir() {
+		return newDirFS(path), name, nil
+	}
+
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return nil, "", fmt.Errorf("template '%s' must be a directory, a .zip file or a git repository", location)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	files, err := Zip(data)
+	return files, name, err
+}
+
+// Zip returns the files of a zip archive, the archive is rejected if an entry would be written outside of the
+// project directory or it declares more files or bytes than the limits
+func Zip(data []byte) (fs.FS, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil && !errors.Is(err, zip.ErrInsecurePath) {
+		return nil, fmt.Errorf("failed to read zip file: %w", err)
+	}
+
+	if len(zipReader.File) > MaxFiles {
+		return nil, fmt.Errorf("%w: more than %d files", ErrUnsafe, MaxFiles)
+	}
+
+	var size uint64
+	for _, file := range zipReader.File {
+		if err := CheckName(file.Name); err != nil {
+			return nil, err
+		}
+
+		size += file.UncompressedSize64
+		if size > MaxSize {
+			return nil, fmt.Errorf("%w: larger than %d bytes", ErrUnsafe, MaxSize)
+		}
+	}
+
+	return zipReader, nil
+}
+
+// splitRef splits the git ref from the location, unless the location exists as it is
+func splitRef(location string) (string, string) {
+	if _, err := os.Stat(location); err == nil {
+		return location, ""
+	}
+
+	i := strings.LastIndex(location, "@")
+	if i <= 0 {
+		return location, ""
+	}
+
+	return location[:i], location[i+1:]
+}
+
+// gitArchive returns the files of the git repository at the ref, the repository is read locally.
+// The ref is resolved to its tree first, so it is never taken as an option of git, like --remote.
+func gitArchive(repository, ref string) (fs.FS, error) {
+	if ref == "" || strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid git ref '%s'", ref)
+	}
+
+	tree, err := git(repository, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{tree}")
+	if err != nil {
+		return nil, fmt.Errorf("unknown git ref '%s' in %s", ref, repository)
+	}
+
+	archive, err := git(repository, "archive", "--format=zip", strings.TrimSpace(string(tree)))
+	if err != nil {
+		return nil, fmt.Errorf("git archive %s failed: %w", ref, err)
+	}
+
+	return Zip(archive)
+}
+
+// git runs a git command in the repository and returns its output
+func git(repository string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", repository}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, errors.New(strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
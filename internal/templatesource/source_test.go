@@ -0,0 +1,14 @@
+package templatesource
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGitArchiveRejectsOptions(t *testing.T) {
+	for _, ref := range []string{"--output=/tmp/pwned", "--remote=example.com:repo", "-o"} {
+		if _, err := gitArchive(t.TempDir(), ref); err == nil || !strings.Contains(err.Error(), "invalid git ref") {
+			t.Errorf("gitArchive(%q) = %v, want an invalid ref", ref, err)
+		}
+	}
+}
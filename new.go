@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/olbrichattila/creategofra/internal/appwizard"
+	"github.com/olbrichattila/creategofra/internal/dockerwizard"
+	"github.com/olbrichattila/creategofra/internal/scaffold"
+	"github.com/olbrichattila/creategofra/internal/specio"
+	"github.com/olbrichattila/creategofra/internal/templatesource"
+	"golang.org/x/mod/module"
+)
+
+func runNew(args []string) int {
+	// the questions of the template are loaded first, so they get flags too
+	var template *templatesource.Template
+	var err error
+	if location := flagValue(args, "template"); location != "" {
+		if template, err = openTemplate(location); err != nil {
+			return report(stepFailed("open template", invalidInput, err))
+		}
+	} else if template, err = presetTemplate(args); err != nil {
+		return report(stepFailed("select project type", invalidInput, err))
+	}
+
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the files, .env and docker-compose.yml instead of writing them")
+	flags.String("template", "", "template directory, .zip file or git repository with optional @ref, instead of --type")
+	quiet := flags.Bool("quiet", false, "do not show the progress of writing the files")
+	modulePath := flags.String("module", "", "module path of the project, like github.com/acme/myapp, the directory name by default")
+	answerFlags, err := newAnswerFlags(flags, args)
+	if err != nil {
+		return report(stepFailed("load questions", invalidInput, err))
+	}
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintln(out, "Usage creategofra new <project-name> [flags]")
+		fmt.Fprintln(out, "\nCreates the project in a new directory, asking the questions not answered by the flags or the answers file.")
+		fmt.Fprintln(out, "\nFlags:")
+		answerFlags.printDefaults(flags)
+	}
+
+	positional, err := parseFlags(flags, args)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(positional) != 1 {
+		fmt.Println("Usage creategofra new <project-name> [flags], see creategofra new --help")
+		return exitUsage
+	}
+
+	options, err := answerFlags.options()
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+
+	specio.Quiet = *quiet
+	if *modulePath == "" {
+		*modulePath = filepath.Base(positional[0])
+	}
+
+	return report(newProject(positional[0], *modulePath, template, options, *dryRun))
+}
+
+// newProject runs the generation steps, the project directory is created only if every step succeeded.
+// The project is generated from the given template, or from the embedded template selected by the user.
+func newProject(projectName, modulePath string, template *templatesource.Template, options appwizard.Options, dryRun bool) error {
+	if validated := validate(projectName); validated != "" {
+		return stepFailed("validate project name", invalidInput, errors.New(validated))
+	}
+
+	if err := module.CheckImportPath(modulePath); err != nil {
+		return stepFailed("validate module path", invalidInput, err)
+	}
+
+	if template == nil {
+		var err error
+		if template, err = selectTemplate(options); err != nil {
+			return err
+		}
+	}
+
+	project := scaffold.New()
+	if err := extract(project, "", template.Files); err != nil {
+		if errors.Is(err, templatesource.ErrUnsafe) {
+			return stepFailed("extract project template", invalidInput, err)
+		}
+		return stepFailed("extract project template", ioFailure, err)
+	}
+
+	envContent, err := project.Read(".env")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return stepFailed("read .env", ioFailure, err)
+	}
+
+	answers := make(map[string]string)
+	options.Record = func(recorded map[string]string) {
+		answers = recorded
+	}
+	responses, storages, envStr, err := appwizard.Wizard(string(envContent), options)
+	if err != nil {
+		return stepFailed("answer questions", invalidInput, err)
+	}
+	// the .env file has the passwords, only the owner can read it
+	project.AddFile(".env", []byte(envStr), 0600)
+
+	if err := copyMigrations(project, responses); err != nil {
+		return stepFailed("extract migrations", ioFailure, err)
+	}
+
+	data := newTemplateData(projectName, modulePath, responses)
+	applyFileGroups(project, template.Manifest, data)
+	if err := renderFiles(project, template.Manifest, data); err != nil {
+		return stepFailed("render templates", invalidInput, err)
+	}
+	applyModes(project, template.Manifest)
+
+	commands, err := postCommands(template.Manifest, data)
+	if err != nil {
+		return stepFailed("render post-generation commands", invalidInput, err)
+	}
+
+	hasMailConfig := hasMailConfig(responses)
+	dbConnectionName := getDbConnection(responses)
+
+	dockerComposeFileContent := dockerwizard.Wizard(dbConnectionName, responses, storages, hasMailConfig)
+	project.Add("docker-compose.yml", []byte(dockerComposeFileContent))
+
+	if dryRun {
+		printDryRun(project, projectName, commands)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	progress := specio.NewProgress("Writing "+projectName, len(project.Files()), project.Size())
+	stage, err := project.Stage(projectName, func(file scaffold.File) {
+		progress.Step(file.Name, file.Size())
+	})
+	progress.Done()
+	if errors.Is(err, errInvalidGo) {
+		return stepFailed("write files", invalidInput, err)
+	}
+
+	if err != nil {
+		return stepFailed("write files", ioFailure, err)
+	}
+	defer func() {
+		if rollbackErr := stage.Rollback(); rollbackErr != nil {
+			fmt.Printf("\nCould not remove staging directory %s: %v\n", stage.Dir, rollbackErr)
+		}
+	}()
+
+	for _, command := range commands {
+		step := "run " + strings.Join(command, " ")
+		if err := runPostStep(ctx, stage.Dir, command); err != nil {
+			if ctx.Err() != nil {
+				return stepFailed(step, userAborted, errAborted)
+			}
+			return stepFailed(step, toolchainFailure, err)
+		}
+	}
+
+	if err := writeRecord(stage.Dir, template, modulePath, answers); err != nil {
+		return stepFailed("write "+recordFile, ioFailure, err)
+	}
+
+	if ctx.Err() != nil {
+		return stepFailed("move project in place", userAborted, errAborted)
+	}
+
+	return stepFailed("move project in place", ioFailure, stage.Commit())
+}
+
+// selectTemplate opens the embedded template of the project type selected by the user
+func selectTemplate(options appwizard.Options) (*templatesource.Template, error) {
+	selection, err := appwizard.ProjectType(options)
+	if err != nil {
+		return nil, stepFailed("select project type", invalidInput, err)
+	}
+
+	template, err := findTemplate(selection)
+	if err != nil {
+		return nil, stepFailed("select project type", invalidInput, err)
+	}
+
+	return template, stepFailed("load template questions", invalidInput, loadTemplateQuestions(template))
+}
+
+func validate(projectName string) string {
+	_, err := os.Stat(projectName)
+	if os.IsNotExist(err) {
+		return ""
+	}
+
+	return fmt.Sprintf("Project '%s' already exists!", projectName)
+}
+
+// extractZip adds the content of an embedded zip archive to the project
+func extractZip(project *scaffold.Project, subFolder string, data *[]byte) error {
+	files, err := templatesource.Zip(*data)
+	if err != nil {
+		return err
+	}
+
+	return extract(project, subFolder, files)
+}
+
+// extract adds the template files to the project, they are rendered once the questions are answered and read
+// when they are written. Symbolic links pointing outside of the project and templates over the size limits are rejected.
+func extract(project *scaffold.Project, subFolder string, files fs.FS) error {
+	i := 0
+	var size int64
+	links := make(map[string]string)
+	err := fs.WalkDir(files, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		if name == "." || name == templatesource.ManifestFile {
+			return nil
+		}
+
+		// the version control data of a template directory is not part of the project
+		if entry.IsDir() && entry.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		i++
+		if i > templatesource.MaxFiles {
+			return fmt.Errorf("%w: more than %d files", templatesource.ErrUnsafe, templatesource.MaxFiles)
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+
+		targetFileName := path.Join(subFolder, name)
+		if entry.IsDir() {
+			project.AddDir(targetFileName, info.Mode())
+			return nil
+		}
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			target, err := templatesource.ReadLink(files, name)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", name, err)
+			}
+
+			if err := templatesource.CheckLink(name, target); err != nil {
+				return err
+			}
+
+			links[name] = target
+			project.AddLink(targetFileName, target)
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", name)
+		}
+
+		size += info.Size()
+		if size > templatesource.MaxSize {
+			return fmt.Errorf("%w: larger than %d bytes", templatesource.ErrUnsafe, templatesource.MaxSize)
+		}
+
+		// the content is read only when the file is rendered or written, one file at a time
+		project.AddSource(targetFileName, info.Size(), info.Mode(), func() (io.ReadCloser, error) {
+			return files.Open(name)
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// a link may point outside of the project through the other links, like b -> a/.. with a -> .
+	return templatesource.CheckLinks(links)
+}
+
+func printDryRun(project *scaffold.Project, projectName string, commands [][]string) {
+	fmt.Printf("\nDry run, nothing is written. Files of %s:\n", projectName)
+	project.Print(os.Stdout)
+	fmt.Printf("  %s (written once the post-generation commands ran)\n", recordFile)
+
+	for _, fileName := range []string{".env", "docker-compose.yml"} {
+		content, _ := project.Read(fileName)
+		fmt.Printf("\n--- %s\n%s\n", fileName, strings.TrimSpace(string(content)))
+	}
+
+	skipped := make([]string, 0, len(commands))
+	for _, command := range commands {
+		skipped = append(skipped, strings.Join(command, " "))
+	}
+	fmt.Printf("\nSkipped: %s\n", strings.Join(skipped, ", "))
+}
+
+// runPostStep runs a post-generation command of the template, like go mod tidy, in the project generated into dir
+func runPostStep(ctx context.Context, dir string, command []string) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s failed: %w\nOutput: %s", strings.Join(command, " "), err, output)
+	}
+
+	return nil
+}
+
+func copyMigrations(project *scaffold.Project, responses []appwizard.EnvData) error {
+	dbConnectionName := getDbConnection(responses)
+
+	switch dbConnectionName {
+	case "sqlite":
+		return extractZip(project, "migrations", &sqliteZipData)
+	case "mysql":
+		return extractZip(project, "migrations", &mysqlZipData)
+	case "pgsql":
+		return extractZip(project, "migrations", &pgsqlZipData)
+	case "firebird":
+		return extractZip(project, "migrations", &firebirdZipData)
+	default:
+		fmt.Print("Skip generating, migrations not set")
+	}
+
+	return nil
+}
+
+func getDbConnection(responses []appwizard.EnvData) string {
+	for _, e := range responses {
+		if e.Key == "DB_CONNECTION" {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+func hasMailConfig(responses []appwizard.EnvData) bool {
+	for _, e := range responses {
+		if e.Key == "SMTP_USER_NAME" {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(item string, data []string) bool {
+	for _, v := range data {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/olbrichattila/creategofra/internal/templatesource"
+)
+
+// recordFile records in the root of the generated project how it was generated
+const recordFile = ".creategofra.json"
+
+// projectRecord is the content of the record file
+type projectRecord struct {
+	// Creategofra is the version of creategofra which generated the project
+	Creategofra string         `json:"creategofra"`
+	Template    recordTemplate `json:"template"`
+	Module      string         `json:"module"`
+	// Answers are the answers of the wizard by .env key or answer key, without the secrets
+	Answers map[string]string `json:"answers"`
+	// Files are the SHA-256 checksums of the generated files by slash separated name, without the .env
+	Files map[string]string `json:"files"`
+}
+
+type recordTemplate struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Location is the --template the project was generated from, empty for the embedded templates
+	Location string `json:"location,omitempty"`
+}
+
+// writeRecord writes the record file into the directory of the generated project,
+// the checksums are of the files in the directory once the post-generation commands ran
+func writeRecord(dir string, template *templatesource.Template, modulePath string, answers map[string]string) error {
+	record := projectRecord{
+		Creategofra: getVersion(),
+		Template: recordTemplate{
+			Name:     template.Manifest.Name,
+			Version:  template.Manifest.Version,
+			Location: template.Location,
+		},
+		Module:  modulePath,
+		Answers: answers,
+		Files:   make(map[string]string),
+	}
+
+	err := filepath.WalkDir(dir, func(fileName string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, fileName)
+		if err != nil {
+			return err
+		}
+
+		name = filepath.ToSlash(name)
+		// the checksum of the .env would help guessing its passwords
+		if name == ".env" || name == recordFile {
+			return nil
+		}
+
+		sum, err := checksum(fileName)
+		if err != nil {
+			return err
+		}
+		record.Files[name] = sum
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to compute checksums: %w", err)
+	}
+
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, recordFile), append(content, '\n'), 0644)
+}
+
+func checksum(fileName string) (string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
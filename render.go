@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/olbrichattila/creategofra/internal/appwizard"
+	"github.com/olbrichattila/creategofra/internal/scaffold"
+	"github.com/olbrichattila/creategofra/internal/templatesource"
+)
+
+// templateData is what the files of a template and its post-generation commands are rendered with
+type templateData struct {
+	// Name is the name of the project directory
+	Name string
+	// Module is the module path of the project
+	Module string
+	// Answers are the answers of the wizard by .env key
+	Answers map[string]string
+}
+
+func newTemplateData(projectName, modulePath string, responses []appwizard.EnvData) templateData {
+	data := templateData{Name: filepath.Base(projectName), Module: modulePath, Answers: make(map[string]string, len(responses))}
+	for _, response := range responses {
+		data.Answers[response.Key] = response.Value
+	}
+
+	return data
+}
+
+// applyFileGroups removes the files of the groups whose condition is not met
+func applyFileGroups(project *scaffold.Project, manifest templatesource.Manifest, data templateData) {
+	for _, group := range manifest.Files {
+		if conditionMet(group.When, manifest.Features, data) {
+			continue
+		}
+
+		removed := make([]string, 0)
+		for _, file := range project.Files() {
+			if !file.IsDir && templatesource.Matches(file.Name, group.Paths) {
+				removed = append(removed, file.Name)
+			}
+		}
+
+		for _, name := range removed {
+			project.Remove(name)
+		}
+	}
+}
+
+// applyModes sets the permissions declared by the manifest, a later declaration wins over an earlier one
+func applyModes(project *scaffold.Project, manifest templatesource.Manifest) {
+	for _, mode := range manifest.Modes {
+		for _, file := range project.Files() {
+			if file.Link == "" && templatesource.Matches(file.Name, mode.Paths) {
+				project.Chmod(file.Name, mode.Perm())
+			}
+		}
+	}
+}
+
+// conditionMet evaluates the condition of a file group, a feature name or KEY=value or KEY!=value of an answer
+func conditionMet(when string, features []string, data templateData) bool {
+	if when == "" {
+		return true
+	}
+
+	if key, value, ok := strings.Cut(when, "!="); ok {
+		return data.Answers[strings.TrimSpace(key)] != strings.TrimSpace(value)
+	}
+
+	if key, value, ok := strings.Cut(when, "="); ok {
+		return data.Answers[strings.TrimSpace(key)] == strings.TrimSpace(value)
+	}
+
+	return contains(when, features)
+}
+
+// errInvalidGo is returned for the Go files of a template which cannot be parsed or formatted
+var errInvalidGo = errors.New("invalid Go file")
+
+// renderFiles renders the project files matching the render patterns of the manifest, and points the imports
+// of the Go files from the module of the template to the module of the project when they are written
+func renderFiles(project *scaffold.Project, manifest templatesource.Manifest, data templateData) error {
+	for _, file := range project.Files() {
+		if file.IsDir || file.Link != "" {
+			continue
+		}
+
+		if templatesource.Matches(file.Name, manifest.Render) {
+			content, err := project.Read(file.Name)
+			if err != nil {
+				return err
+			}
+
+			rendered, err := render(file.Name, string(content), manifest.Delimiters, data)
+			if err != nil {
+				return err
+			}
+			project.Add(file.Name, []byte(rendered))
+		}
+
+		if path.Ext(file.Name) == ".go" {
+			name := file.Name
+			project.Transform(name, func(content []byte) ([]byte, error) {
+				return rewriteImports(name, content, manifest.Module, data.Module)
+			})
+		}
+	}
+
+	return nil
+}
+
+// rewriteImports replaces the module of the template with the module of the project in the import paths of a Go file,
+// string literals and comments are left as they are, the result is gofmt-ed
+func rewriteImports(name string, content []byte, templateModule, module string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %w", errInvalidGo, name, err)
+	}
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: invalid import %s", errInvalidGo, name, spec.Path.Value)
+		}
+
+		if importPath == templateModule || strings.HasPrefix(importPath, templateModule+"/") {
+			spec.Path.Value = strconv.Quote(module + strings.TrimPrefix(importPath, templateModule))
+		}
+	}
+	ast.SortImports(fset, file)
+
+	var result bytes.Buffer
+	if err := format.Node(&result, fset, file); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", errInvalidGo, name, err)
+	}
+
+	return result.Bytes(), nil
+}
+
+// postCommands renders the arguments of the post-generation commands of the manifest
+func postCommands(manifest templatesource.Manifest, data templateData) ([][]string, error) {
+	commands := make([][]string, 0, len(manifest.Post))
+	for _, post := range manifest.Post {
+		if len(post) == 0 {
+			continue
+		}
+
+		command := make([]string, 0, len(post))
+		for _, arg := range post {
+			rendered, err := render("post command "+post[0], arg, manifest.Delimiters, data)
+			if err != nil {
+				return nil, err
+			}
+			command = append(command, rendered)
+		}
+		commands = append(commands, command)
+	}
+
+	return commands, nil
+}
+
+func render(name, text string, delimiters []string, data templateData) (string, error) {
+	tmpl, err := template.New(name).Delims(delimiters[0], delimiters[1]).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	var result bytes.Buffer
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+
+	return result.String(), nil
+}
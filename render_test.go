@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRewriteImports(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		invalid bool
+	}{
+		{
+			name:    "module",
+			content: "package main\n\nimport \"gofraapp\"\n",
+			want:    "package main\n\nimport \"github.com/acme/myapp\"\n",
+		},
+		{
+			name:    "subpackage",
+			content: "package main\n\nimport (\n\t\"fmt\"\n\n\t\"gofraapp/app/controllers\"\n)\n",
+			want:    "package main\n\nimport (\n\t\"fmt\"\n\n\t\"github.com/acme/myapp/app/controllers\"\n)\n",
+		},
+		{
+			name:    "other module with the same prefix",
+			content: "package main\n\nimport \"gofraapp2/x\"\n",
+			want:    "package main\n\nimport \"gofraapp2/x\"\n",
+		},
+		{
+			name:    "string literal and comment",
+			content: "package main\n\n// see gofraapp/x\nvar path = \"gofraapp/x\"\n",
+			want:    "package main\n\n// see gofraapp/x\nvar path = \"gofraapp/x\"\n",
+		},
+		{
+			name:    "does not parse",
+			content: "package main\n\nfunc {\n",
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := rewriteImports("main.go", []byte(test.content), "gofraapp", "github.com/acme/myapp")
+		if test.invalid {
+			if !errors.Is(err, errInvalidGo) {
+				t.Errorf("%s: rewriteImports() = %v, want errInvalidGo", test.name, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: rewriteImports() = %v", test.name, err)
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%s: rewriteImports() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/olbrichattila/creategofra/internal/specio"
+)
+
+type failureKind int
+
+const (
+	invalidInput failureKind = iota
+	ioFailure
+	toolchainFailure
+	userAborted
+)
+
+// errAborted is returned when the user interrupted a step, with Ctrl-C in a prompt or with a signal
+var errAborted = specio.ErrAborted
+
+// stepError is returned by a generation step, the step is named in the report and the kind selects the exit code
+type stepError struct {
+	step string
+	kind failureKind
+	err  error
+}
+
+func (e *stepError) Error() string {
+	return e.step + ": " + e.err.Error()
+}
+
+func (e *stepError) Unwrap() error {
+	return e.err
+}
+
+func (e *stepError) exitCode() int {
+	switch e.kind {
+	case ioFailure:
+		return exitIO
+	case toolchainFailure:
+		return exitToolchain
+	case userAborted:
+		return exitAborted
+	}
+
+	return exitFailure
+}
+
+// stepFailed wraps the error of a step, an abort is always reported as such whatever step it happened in
+func stepFailed(step string, kind failureKind, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, errAborted) {
+		kind = userAborted
+	}
+
+	return &stepError{step: step, kind: kind, err: err}
+}
+
+// report prints the result of a command and returns its exit code
+func report(err error) int {
+	if err == nil {
+		fmt.Print("\nDone\n")
+		return exitOK
+	}
+
+	var stepErr *stepError
+	if !errors.As(err, &stepErr) {
+		fmt.Printf("\nFailed: %v\n", err)
+		return exitFailure
+	}
+
+	if stepErr.kind == userAborted {
+		fmt.Printf("\nAborted during step '%s', nothing is written\n", stepErr.step)
+		return stepErr.exitCode()
+	}
+
+	fmt.Printf("\nFailed at step '%s': %v\n", stepErr.step, stepErr.err)
+	return stepErr.exitCode()
+}
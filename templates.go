@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/olbrichattila/creategofra/internal/appwizard"
+	"github.com/olbrichattila/creategofra/internal/templatesource"
+)
+
+// embeddedTemplates are the templates built in the binary, they are selected by the name in their manifest
+var embeddedTemplates = []*[]byte{&blankAppZipData, &regAppZipData}
+
+func findTemplate(name string) (*templatesource.Template, error) {
+	for _, data := range embeddedTemplates {
+		template, err := templatesource.Embedded(*data)
+		if err != nil {
+			return nil, err
+		}
+
+		if template.Manifest.Name == name {
+			return template, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown project template '%s'", name)
+}
+
+// openTemplate opens a template given by --template and adds the questions of its manifest to the wizard
+func openTemplate(location string) (*templatesource.Template, error) {
+	template, err := templatesource.Open(location)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, loadTemplateQuestions(template)
+}
+
+// presetTemplate opens the embedded template given by --type or by the answers file and adds the questions of its
+// manifest to the wizard, before the flags are built so the questions get flags too. Without a preset type
+// the template is selected once the flags are parsed.
+func presetTemplate(args []string) (*templatesource.Template, error) {
+	answers := make(appwizard.Answers)
+	if answersFile := flagValue(args, "answers"); answersFile != "" {
+		var err error
+		if answers, err = appwizard.LoadAnswers(answersFile); err != nil {
+			return nil, err
+		}
+	}
+
+	projectType, ok := appwizard.PresetProjectType(answers, flagValue(args, "type"))
+	if !ok {
+		return nil, nil
+	}
+
+	template, err := findTemplate(projectType)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, loadTemplateQuestions(template)
+}
+
+func loadTemplateQuestions(template *templatesource.Template) error {
+	content, err := template.Manifest.QuestionsFile()
+	if err != nil || content == nil {
+		return err
+	}
+
+	return appwizard.ExtendQuestions("template "+template.Manifest.Name, content)
+}
+
+func runTemplates(args []string) int {
+	flags := flag.NewFlagSet("templates", flag.ContinueOnError)
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintln(out, "Usage creategofra templates list")
+		fmt.Fprintln(out, "\nLists the project templates, the name can be used as --type of creategofra new.")
+	}
+
+	positional, err := parseFlags(flags, args)
+	if err == flag.ErrHelp {
+		return exitOK
+	}
+
+	if err != nil {
+		return exitUsage
+	}
+
+	if len(positional) != 1 || positional[0] != "list" {
+		flags.Usage()
+		return exitUsage
+	}
+
+	for _, data := range embeddedTemplates {
+		template, err := templatesource.Embedded(*data)
+		if err != nil {
+			fmt.Println(err)
+			return exitFailure
+		}
+		fmt.Printf("%-10s %s\n", template.Manifest.Name, template.Manifest.Description)
+	}
+
+	return exitOK
+}